@@ -0,0 +1,141 @@
+package apiserver
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+
+	"github.com/babylonchain/staking-indexer/indexerstore"
+)
+
+func (s *Server) handleGetStakingTxByHash(w http.ResponseWriter, r *http.Request) {
+	hash, err := parseHashParam(r, "tx_hash")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tx, err := s.si.GetStakingTxByHash(hash)
+	if err != nil {
+		writeStoreErr(w, err)
+		return
+	}
+
+	writeJSON(w, tx)
+}
+
+func (s *Server) handleListStakesByStakerPk(w http.ResponseWriter, r *http.Request) {
+	pk, err := parsePkParam(r, "staker_pk")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	txs, err := s.si.ListStakesByStakerPk(pk)
+	if err != nil {
+		writeStoreErr(w, err)
+		return
+	}
+
+	writeJSON(w, txs)
+}
+
+func (s *Server) handleListStakesByFinalityProviderPk(w http.ResponseWriter, r *http.Request) {
+	pk, err := parsePkParam(r, "finality_provider_pk")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	txs, err := s.si.ListStakesByFinalityProviderPk(pk)
+	if err != nil {
+		writeStoreErr(w, err)
+		return
+	}
+
+	writeJSON(w, txs)
+}
+
+func (s *Server) handleListActiveStakesAtHeight(w http.ResponseWriter, r *http.Request) {
+	height, err := strconv.ParseUint(r.URL.Query().Get("height"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid height"))
+		return
+	}
+
+	txs, err := s.si.ListActiveStakesAtHeight(height)
+	if err != nil {
+		writeStoreErr(w, err)
+		return
+	}
+
+	writeJSON(w, txs)
+}
+
+func (s *Server) handleListUnbondingsByStakingTxHash(w http.ResponseWriter, r *http.Request) {
+	hash, err := parseHashParam(r, "staking_tx_hash")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tx, err := s.si.ListUnbondingsByStakingTxHash(hash)
+	if err != nil {
+		writeStoreErr(w, err)
+		return
+	}
+
+	writeJSON(w, tx)
+}
+
+func (s *Server) handleGetIndexerStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, s.si.GetIndexerStatus())
+}
+
+func parseHashParam(r *http.Request, name string) (*chainhash.Hash, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, errors.New("missing " + name)
+	}
+
+	return chainhash.NewHashFromStr(raw)
+}
+
+func parsePkParam(r *http.Request, name string) (*btcec.PublicKey, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, errors.New("missing " + name)
+	}
+
+	pkBytes, err := hex.DecodeString(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return btcec.ParsePubKey(pkBytes)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+func writeStoreErr(w http.ResponseWriter, err error) {
+	if errors.Is(err, indexerstore.ErrTransactionNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	writeError(w, http.StatusInternalServerError, err)
+}
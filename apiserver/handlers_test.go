@@ -0,0 +1,189 @@
+package apiserver
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/staking-indexer/config"
+	"github.com/babylonchain/staking-indexer/indexer"
+	"github.com/babylonchain/staking-indexer/indexerstore"
+	"github.com/babylonchain/staking-indexer/testutils/datagen"
+	"github.com/babylonchain/staking-indexer/testutils/mocks"
+	"github.com/babylonchain/staking-indexer/types"
+)
+
+// newTestIndexer builds a StakingIndexer backed by a real on-disk store
+// and a mocked scanner/consumer, suitable for exercising the apiserver
+// handlers without running the indexer's processing loops.
+func newTestIndexer(t *testing.T) (*indexer.StakingIndexer, *types.Params) {
+	cfg := config.DefaultConfigWithHome(t.TempDir())
+
+	db, err := cfg.DatabaseConfig.GetDbBackend()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	r := rand.New(rand.NewSource(1))
+	sysParams := datagen.GenerateGlobalParams(r, t)
+
+	ctrl := gomock.NewController(t)
+	mockScanner := mocks.NewMockBtcScanner(ctrl)
+	mockScanner.EXPECT().ConfirmedBlocksChan().Return(make(chan *types.IndexedBlock)).AnyTimes()
+	mockScanner.EXPECT().MempoolTxsChan().Return(make(<-chan *btcutil.Tx)).AnyTimes()
+	mockScanner.EXPECT().ReorgChan().Return(make(<-chan int32)).AnyTimes()
+
+	mockConsumer := mocks.NewMockEventConsumer(ctrl)
+	mockConsumer.EXPECT().PushStakingEvent(gomock.Any()).Return(nil).AnyTimes()
+
+	si, err := indexer.NewStakingIndexer(cfg, zap.NewNop(), mockConsumer, db, sysParams, mockScanner)
+	require.NoError(t, err)
+
+	return si, sysParams
+}
+
+func newTestServer(t *testing.T, si *indexer.StakingIndexer) *httptest.Server {
+	srv := New(config.DefaultApiServerConfig(), zap.NewNop(), si)
+	ts := httptest.NewServer(srv.router())
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestHandleGetStakingTxByHash(t *testing.T) {
+	si, sysParams := newTestIndexer(t)
+	r := rand.New(rand.NewSource(2))
+
+	stakingData := datagen.GenerateTestStakingData(t, r)
+	parsed, tx := datagen.GenerateStakingTxFromTestData(t, r, sysParams, stakingData)
+	require.NoError(t, si.ProcessStakingTx(tx.MsgTx(), parsed, 100, time.Now()))
+
+	ts := newTestServer(t, si)
+
+	resp, err := http.Get(ts.URL + "/v1/staking-tx?tx_hash=" + tx.MsgTx().TxHash().String())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(ts.URL + "/v1/staking-tx?tx_hash=" + strings.Repeat("00", 32))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Get(ts.URL + "/v1/staking-tx")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandleListStakesByStakerPk(t *testing.T) {
+	si, sysParams := newTestIndexer(t)
+	r := rand.New(rand.NewSource(3))
+
+	stakingData := datagen.GenerateTestStakingData(t, r)
+	parsed, tx := datagen.GenerateStakingTxFromTestData(t, r, sysParams, stakingData)
+	require.NoError(t, si.ProcessStakingTx(tx.MsgTx(), parsed, 100, time.Now()))
+
+	ts := newTestServer(t, si)
+
+	pkHex := hex.EncodeToString(stakingData.StakerKey.SerializeCompressed())
+	resp, err := http.Get(ts.URL + "/v1/stakes/by-staker?staker_pk=" + pkHex)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(ts.URL + "/v1/stakes/by-staker")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandleListStakesByFinalityProviderPk(t *testing.T) {
+	si, sysParams := newTestIndexer(t)
+	r := rand.New(rand.NewSource(4))
+
+	stakingData := datagen.GenerateTestStakingData(t, r)
+	parsed, tx := datagen.GenerateStakingTxFromTestData(t, r, sysParams, stakingData)
+	require.NoError(t, si.ProcessStakingTx(tx.MsgTx(), parsed, 100, time.Now()))
+
+	ts := newTestServer(t, si)
+
+	pkHex := hex.EncodeToString(stakingData.FinalityProviderKey.SerializeCompressed())
+	resp, err := http.Get(ts.URL + "/v1/stakes/by-finality-provider?finality_provider_pk=" + pkHex)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(ts.URL + "/v1/stakes/by-finality-provider")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+// TestHandleListActiveStakesAtHeight covers the height-boundary behavior
+// ListActiveStakesAtHeight relies on: a stake included at a height
+// strictly before the queried height must still be reported as active.
+func TestHandleListActiveStakesAtHeight(t *testing.T) {
+	si, sysParams := newTestIndexer(t)
+	r := rand.New(rand.NewSource(5))
+
+	stakingData := datagen.GenerateTestStakingData(t, r)
+	parsed, tx := datagen.GenerateStakingTxFromTestData(t, r, sysParams, stakingData)
+	require.NoError(t, si.ProcessStakingTx(tx.MsgTx(), parsed, 100, time.Now()))
+
+	ts := newTestServer(t, si)
+
+	resp, err := http.Get(ts.URL + "/v1/stakes/active-at-height?height=150")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var txs []*indexerstore.StoredStakingTransaction
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&txs))
+	require.Len(t, txs, 1)
+	require.Equal(t, tx.MsgTx().TxHash(), txs[0].Tx.TxHash())
+
+	resp, err = http.Get(ts.URL + "/v1/stakes/active-at-height?height=99")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var empty []*indexerstore.StoredStakingTransaction
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&empty))
+	require.Empty(t, empty)
+
+	resp, err = http.Get(ts.URL + "/v1/stakes/active-at-height?height=notanumber")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandleListUnbondingsByStakingTxHash(t *testing.T) {
+	si, _ := newTestIndexer(t)
+	ts := newTestServer(t, si)
+
+	resp, err := http.Get(ts.URL + "/v1/unbondings/by-staking-tx?staking_tx_hash=" + strings.Repeat("11", 32))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandleGetIndexerStatus(t *testing.T) {
+	si, sysParams := newTestIndexer(t)
+	ts := newTestServer(t, si)
+
+	resp, err := http.Get(ts.URL + "/v1/status")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, sysParams.Version, si.GetIndexerStatus().ParamsVersion)
+}
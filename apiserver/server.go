@@ -0,0 +1,99 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/staking-indexer/config"
+	"github.com/babylonchain/staking-indexer/indexer"
+)
+
+// Server is an HTTP query API layered on top of a StakingIndexer,
+// letting external consumers ask about stakes by staker, by finality
+// provider, or by BTC height without having to run their own copy of
+// the indexer.
+type Server struct {
+	startOnce sync.Once
+	stopOnce  sync.Once
+
+	logger *zap.Logger
+	cfg    *config.ApiServerConfig
+
+	si *indexer.StakingIndexer
+
+	httpServer *http.Server
+}
+
+// New creates a new Server that serves queries against si according
+// to cfg.
+func New(cfg *config.ApiServerConfig, logger *zap.Logger, si *indexer.StakingIndexer) *Server {
+	s := &Server{
+		logger: logger,
+		cfg:    cfg,
+		si:     si,
+	}
+
+	s.httpServer = &http.Server{
+		Addr:    net.JoinHostPort(cfg.Host, fmt.Sprintf("%d", cfg.Port)),
+		Handler: s.router(),
+	}
+
+	return s
+}
+
+// Start starts serving the API in the background. It is a no-op if
+// the server is disabled in its config.
+func (s *Server) Start() error {
+	if !s.cfg.Enable {
+		return nil
+	}
+
+	var startErr error
+	s.startOnce.Do(func() {
+		ln, err := net.Listen("tcp", s.httpServer.Addr)
+		if err != nil {
+			startErr = fmt.Errorf("failed to listen on %s: %w", s.httpServer.Addr, err)
+			return
+		}
+
+		go func() {
+			if err := s.httpServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				s.logger.Error("apiserver stopped unexpectedly", zap.Error(err))
+			}
+		}()
+	})
+
+	return startErr
+}
+
+// Stop gracefully shuts the API server down.
+func (s *Server) Stop() error {
+	if !s.cfg.Enable {
+		return nil
+	}
+
+	var stopErr error
+	s.stopOnce.Do(func() {
+		stopErr = s.httpServer.Shutdown(context.Background())
+	})
+
+	return stopErr
+}
+
+func (s *Server) router() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/staking-tx", s.handleGetStakingTxByHash)
+	mux.HandleFunc("/v1/stakes/by-staker", s.handleListStakesByStakerPk)
+	mux.HandleFunc("/v1/stakes/by-finality-provider", s.handleListStakesByFinalityProviderPk)
+	mux.HandleFunc("/v1/stakes/active-at-height", s.handleListActiveStakesAtHeight)
+	mux.HandleFunc("/v1/unbondings/by-staking-tx", s.handleListUnbondingsByStakingTxHash)
+	mux.HandleFunc("/v1/status", s.handleGetIndexerStatus)
+
+	return mux
+}
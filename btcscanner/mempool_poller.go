@@ -0,0 +1,143 @@
+// Package btcscanner provides a types.BtcScanner-compatible mempool
+// poller backed by a real btcd/bitcoind node, polling getrawmempool on
+// an interval rather than requiring a ZMQ subscription.
+package btcscanner
+
+import (
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/rpcclient"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/staking-indexer/config"
+)
+
+// RpcClient is the subset of a btcd/bitcoind RPC client the mempool
+// poller needs to observe the node's mempool.
+type RpcClient interface {
+	GetRawMempool() ([]*chainhash.Hash, error)
+	GetRawTransaction(txHash *chainhash.Hash) (*btcutil.Tx, error)
+}
+
+var _ RpcClient = (*rpcclient.Client)(nil)
+
+// NewRpcClient dials the node described by cfg and returns an RpcClient
+// backed by it.
+func NewRpcClient(cfg *config.BtcScannerConfig) (*rpcclient.Client, error) {
+	return rpcclient.New(&rpcclient.ConnConfig{
+		Host:         cfg.RpcHost,
+		User:         cfg.RpcUser,
+		Pass:         cfg.RpcPass,
+		DisableTLS:   cfg.DisableTls,
+		HTTPPostMode: true,
+	}, nil)
+}
+
+// MempoolPoller polls a BTC node's mempool via getrawmempool/
+// getrawtransaction on a fixed interval, delivering every
+// not-previously-seen transaction exactly once on TxsChan. TxsChan
+// carries the feed a types.BtcScanner delivers on MempoolTxsChan, but
+// the poller does not itself implement types.BtcScanner: it has no
+// MempoolTxsChan, ConfirmedBlocksChan or ReorgChan method.
+type MempoolPoller struct {
+	wg   sync.WaitGroup
+	quit chan struct{}
+
+	client   RpcClient
+	logger   *zap.Logger
+	interval time.Duration
+
+	txsChan chan *btcutil.Tx
+
+	seen map[chainhash.Hash]struct{}
+}
+
+// NewMempoolPoller creates a MempoolPoller that queries client every
+// interval.
+func NewMempoolPoller(client RpcClient, interval time.Duration, logger *zap.Logger) *MempoolPoller {
+	return &MempoolPoller{
+		quit:     make(chan struct{}),
+		client:   client,
+		logger:   logger,
+		interval: interval,
+		txsChan:  make(chan *btcutil.Tx),
+		seen:     make(map[chainhash.Hash]struct{}),
+	}
+}
+
+// TxsChan returns the channel every newly observed mempool transaction
+// is delivered on.
+func (p *MempoolPoller) TxsChan() <-chan *btcutil.Tx {
+	return p.txsChan
+}
+
+// Start begins polling the node's mempool in the background.
+func (p *MempoolPoller) Start() {
+	p.wg.Add(1)
+	go p.pollLoop()
+}
+
+// Stop signals the poll loop to exit and waits for it to return.
+func (p *MempoolPoller) Stop() {
+	close(p.quit)
+	p.wg.Wait()
+}
+
+func (p *MempoolPoller) pollLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.poll()
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+func (p *MempoolPoller) poll() {
+	hashes, err := p.client.GetRawMempool()
+	if err != nil {
+		p.logger.Error("failed to fetch mempool", zap.Error(err))
+		return
+	}
+
+	live := make(map[chainhash.Hash]struct{}, len(hashes))
+	for _, h := range hashes {
+		live[*h] = struct{}{}
+
+		if _, ok := p.seen[*h]; ok {
+			continue
+		}
+
+		tx, err := p.client.GetRawTransaction(h)
+		if err != nil {
+			p.logger.Error("failed to fetch mempool tx",
+				zap.String("tx_hash", h.String()), zap.Error(err))
+			continue
+		}
+
+		p.seen[*h] = struct{}{}
+
+		select {
+		case p.txsChan <- tx:
+		case <-p.quit:
+			return
+		}
+	}
+
+	// Evict hashes that have left the mempool (confirmed or dropped)
+	// so the seen set doesn't grow without bound.
+	for h := range p.seen {
+		if _, ok := live[h]; !ok {
+			delete(p.seen, h)
+		}
+	}
+}
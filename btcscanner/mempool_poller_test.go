@@ -0,0 +1,124 @@
+package btcscanner_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/staking-indexer/btcscanner"
+)
+
+// fakeRpcClient is an in-memory stand-in for a btcd/bitcoind RPC
+// client, used to drive MempoolPoller without a real node.
+type fakeRpcClient struct {
+	mu      sync.Mutex
+	mempool map[chainhash.Hash]*btcutil.Tx
+}
+
+func newFakeRpcClient() *fakeRpcClient {
+	return &fakeRpcClient{mempool: make(map[chainhash.Hash]*btcutil.Tx)}
+}
+
+func (f *fakeRpcClient) addTx(tx *btcutil.Tx) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mempool[*tx.Hash()] = tx
+}
+
+func (f *fakeRpcClient) removeTx(hash chainhash.Hash) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.mempool, hash)
+}
+
+func (f *fakeRpcClient) GetRawMempool() ([]*chainhash.Hash, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	hashes := make([]*chainhash.Hash, 0, len(f.mempool))
+	for h := range f.mempool {
+		h := h
+		hashes = append(hashes, &h)
+	}
+	return hashes, nil
+}
+
+func (f *fakeRpcClient) GetRawTransaction(txHash *chainhash.Hash) (*btcutil.Tx, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tx, ok := f.mempool[*txHash]
+	if !ok {
+		return nil, errors.New("no such mempool tx")
+	}
+	return tx, nil
+}
+
+func randTx(lockTime uint32) *btcutil.Tx {
+	msgTx := wire.NewMsgTx(wire.TxVersion)
+	msgTx.LockTime = lockTime
+	return btcutil.NewTx(msgTx)
+}
+
+// TestMempoolPollerDeliversNewTxsOnce asserts a transaction already
+// present in the node's mempool is delivered on TxsChan exactly once,
+// and is not redelivered on subsequent polls.
+func TestMempoolPollerDeliversNewTxsOnce(t *testing.T) {
+	client := newFakeRpcClient()
+	tx := randTx(1)
+	client.addTx(tx)
+
+	poller := btcscanner.NewMempoolPoller(client, 5*time.Millisecond, zap.NewNop())
+	poller.Start()
+	defer poller.Stop()
+
+	select {
+	case got := <-poller.TxsChan():
+		require.Equal(t, tx.Hash(), got.Hash())
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for mempool tx")
+	}
+
+	select {
+	case got := <-poller.TxsChan():
+		t.Fatalf("tx %s redelivered", got.Hash())
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// TestMempoolPollerEvictsConfirmedTxs asserts a tx removed from the
+// node's mempool (e.g. because it confirmed) is forgotten, so that if
+// an identical txid somehow reappeared it would be treated as new.
+func TestMempoolPollerEvictsConfirmedTxs(t *testing.T) {
+	client := newFakeRpcClient()
+	tx := randTx(2)
+	client.addTx(tx)
+
+	poller := btcscanner.NewMempoolPoller(client, 5*time.Millisecond, zap.NewNop())
+	poller.Start()
+	defer poller.Stop()
+
+	select {
+	case <-poller.TxsChan():
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for mempool tx")
+	}
+
+	client.removeTx(*tx.Hash())
+	time.Sleep(20 * time.Millisecond)
+
+	client.addTx(tx)
+	select {
+	case got := <-poller.TxsChan():
+		require.Equal(t, tx.Hash(), got.Hash())
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for re-added mempool tx")
+	}
+}
@@ -0,0 +1,24 @@
+package config
+
+// ApiServerConfig holds the configuration for the optional HTTP query
+// API served on top of the indexer.
+type ApiServerConfig struct {
+	// Enable controls whether the API server is started alongside the
+	// indexer.
+	Enable bool `mapstructure:"enable"`
+	// Host is the address the API server listens on.
+	Host string `mapstructure:"host"`
+	// Port is the port the API server listens on.
+	Port int `mapstructure:"port"`
+}
+
+// DefaultApiServerConfig returns the API server configuration used
+// when none is supplied, with the server disabled by default so
+// embedding the indexer as a library doesn't open a port by surprise.
+func DefaultApiServerConfig() *ApiServerConfig {
+	return &ApiServerConfig{
+		Enable: false,
+		Host:   "127.0.0.1",
+		Port:   9792,
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import "time"
+
+// DefaultMempoolPollInterval is how often the mempool poller queries
+// the configured node's mempool when none is supplied.
+const DefaultMempoolPollInterval = 10 * time.Second
+
+// BtcScannerConfig holds the configuration for connecting to a
+// btcd/bitcoind node's RPC interface to observe its mempool.
+type BtcScannerConfig struct {
+	// Enable controls whether the mempool poller is started alongside
+	// the indexer.
+	Enable bool `mapstructure:"enable"`
+	// RpcHost is the host:port of the node's RPC endpoint.
+	RpcHost string `mapstructure:"rpc-host"`
+	// RpcUser is the username used to authenticate to the node's RPC
+	// endpoint.
+	RpcUser string `mapstructure:"rpc-user"`
+	// RpcPass is the password used to authenticate to the node's RPC
+	// endpoint.
+	RpcPass string `mapstructure:"rpc-pass"`
+	// DisableTls disables TLS when connecting to the node's RPC
+	// endpoint, e.g. for a bitcoind node reachable only over
+	// loopback/a private network.
+	DisableTls bool `mapstructure:"disable-tls"`
+	// PollInterval is how often the node's mempool is polled for new
+	// transactions.
+	PollInterval time.Duration `mapstructure:"poll-interval"`
+}
+
+// DefaultBtcScannerConfig returns the mempool scanner configuration
+// used when none is supplied, with the poller disabled by default so
+// embedding the indexer as a library doesn't dial out to a node by
+// surprise.
+func DefaultBtcScannerConfig() *BtcScannerConfig {
+	return &BtcScannerConfig{
+		Enable:       false,
+		RpcHost:      "127.0.0.1:8332",
+		DisableTls:   true,
+		PollInterval: DefaultMempoolPollInterval,
+	}
+}
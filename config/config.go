@@ -0,0 +1,34 @@
+package config
+
+import "path/filepath"
+
+const (
+	defaultDataDirname = "data"
+	defaultDbFilename  = "indexer.db"
+)
+
+// Config is the top level configuration of the staking indexer.
+type Config struct {
+	DatabaseConfig   *DatabaseConfig   `mapstructure:"database"`
+	ApiServerConfig  *ApiServerConfig  `mapstructure:"apiserver"`
+	BtcScannerConfig *BtcScannerConfig `mapstructure:"btcscanner"`
+}
+
+// DatabaseConfig holds the configuration for the indexer's local store.
+type DatabaseConfig struct {
+	// Path is the full path to the bbolt database file backing the
+	// indexer store.
+	Path string `mapstructure:"path"`
+}
+
+// DefaultConfigWithHome returns the default configuration rooted at the
+// given home directory.
+func DefaultConfigWithHome(homePath string) *Config {
+	return &Config{
+		DatabaseConfig: &DatabaseConfig{
+			Path: filepath.Join(homePath, defaultDataDirname, defaultDbFilename),
+		},
+		ApiServerConfig:  DefaultApiServerConfig(),
+		BtcScannerConfig: DefaultBtcScannerConfig(),
+	}
+}
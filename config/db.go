@@ -0,0 +1,27 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+const dbTimeout = 10 * time.Second
+
+// GetDbBackend opens (creating if necessary) the bbolt-backed kvdb
+// database used by the indexer store.
+func (dbCfg *DatabaseConfig) GetDbBackend() (kvdb.Backend, error) {
+	dir := filepath.Dir(dbCfg.Path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return kvdb.Create(
+		kvdb.BoltBackendName,
+		dbCfg.Path,
+		true,
+		dbTimeout,
+	)
+}
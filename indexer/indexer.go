@@ -0,0 +1,423 @@
+package indexer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/babylonchain/babylon/btcstaking"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/staking-indexer/config"
+	"github.com/babylonchain/staking-indexer/indexerstore"
+	"github.com/babylonchain/staking-indexer/types"
+)
+
+// ErrInvalidUnbondingTx is returned by IsValidUnbondingTx when the
+// given transaction does not satisfy the unbonding requirements
+// dictated by the params version its staking transaction was indexed
+// under.
+var ErrInvalidUnbondingTx = errors.New("invalid unbonding tx")
+
+// StakingIndexer consumes confirmed BTC blocks from a types.BtcScanner,
+// extracts staking/unbonding transactions from them and persists them
+// in the local store, pushing events about them to a types.EventConsumer.
+type StakingIndexer struct {
+	startOnce sync.Once
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+	quit      chan struct{}
+
+	logger *zap.Logger
+	cfg    *config.Config
+
+	is *indexerstore.IndexerStore
+
+	btcScanner types.BtcScanner
+	consumer   types.EventConsumer
+
+	// paramsMu guards paramsVersions, which is append-only after
+	// construction but may be extended at runtime via AddParams while
+	// the processing loops are reading it.
+	paramsMu sync.RWMutex
+	// paramsVersions holds every known staking params version, sorted
+	// ascending by ActivationHeight.
+	paramsVersions []*types.Params
+
+	// lastProcessedHeight is the BTC height of the last confirmed
+	// block the indexer has finished processing. Accessed atomically.
+	lastProcessedHeight int64
+}
+
+// NewStakingIndexer creates a new StakingIndexer backed by db, driven
+// by the given scanner, and reporting events to consumer.
+func NewStakingIndexer(
+	cfg *config.Config,
+	logger *zap.Logger,
+	consumer types.EventConsumer,
+	db kvdb.Backend,
+	params *types.Params,
+	scanner types.BtcScanner,
+) (*StakingIndexer, error) {
+	is, err := indexerstore.NewIndexerStore(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create indexer store: %w", err)
+	}
+
+	si := &StakingIndexer{
+		quit:           make(chan struct{}),
+		logger:         logger,
+		cfg:            cfg,
+		is:             is,
+		btcScanner:     scanner,
+		consumer:       consumer,
+		paramsVersions: []*types.Params{params},
+	}
+
+	lastHeight, found, err := is.GetLastProcessedHeight()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load last processed height: %w", err)
+	}
+	if found {
+		si.lastProcessedHeight = int64(lastHeight)
+	}
+
+	return si, nil
+}
+
+// AddParams registers an additional staking params version, active
+// from its ActivationHeight, alongside the ones the indexer already
+// knows about. It returns an error if a version with the same Version
+// number is already registered.
+func (si *StakingIndexer) AddParams(params *types.Params) error {
+	si.paramsMu.Lock()
+	defer si.paramsMu.Unlock()
+
+	for _, existing := range si.paramsVersions {
+		if existing.Version == params.Version {
+			return fmt.Errorf("params version %d is already registered", params.Version)
+		}
+	}
+
+	si.paramsVersions = append(si.paramsVersions, params)
+	sort.Slice(si.paramsVersions, func(i, j int) bool {
+		return si.paramsVersions[i].ActivationHeight < si.paramsVersions[j].ActivationHeight
+	})
+
+	return nil
+}
+
+// paramsAtHeight returns the params version active at the given BTC
+// height, i.e. the one with the highest ActivationHeight that is still
+// at or below height. If height predates every known version, the
+// earliest known version is returned.
+func (si *StakingIndexer) paramsAtHeight(height int32) *types.Params {
+	si.paramsMu.RLock()
+	defer si.paramsMu.RUnlock()
+
+	selected := si.paramsVersions[0]
+	for _, p := range si.paramsVersions {
+		if p.ActivationHeight > height {
+			break
+		}
+		selected = p
+	}
+
+	return selected
+}
+
+// paramsByVersion returns the params version identified by version,
+// e.g. the one a previously stored staking tx was indexed under.
+func (si *StakingIndexer) paramsByVersion(version uint32) (*types.Params, bool) {
+	si.paramsMu.RLock()
+	defer si.paramsMu.RUnlock()
+
+	for _, p := range si.paramsVersions {
+		if p.Version == version {
+			return p, true
+		}
+	}
+
+	return nil, false
+}
+
+// latestParams returns the params version with the highest
+// ActivationHeight known to the indexer, whether or not the chain has
+// reached that height yet. It is used to parse transactions that have
+// not confirmed yet and therefore have no associated height to look
+// up a version by.
+func (si *StakingIndexer) latestParams() *types.Params {
+	si.paramsMu.RLock()
+	defer si.paramsMu.RUnlock()
+
+	return si.paramsVersions[len(si.paramsVersions)-1]
+}
+
+// Start starts the consumer, the underlying BTC scanner from the given
+// height, and the indexer's confirmed-block processing loop.
+func (si *StakingIndexer) Start(startHeight int32) error {
+	var startErr error
+	si.startOnce.Do(func() {
+		if err := si.consumer.Start(); err != nil {
+			startErr = fmt.Errorf("failed to start event consumer: %w", err)
+			return
+		}
+
+		if err := si.btcScanner.Start(startHeight); err != nil {
+			startErr = fmt.Errorf("failed to start btc scanner: %w", err)
+			return
+		}
+
+		si.wg.Add(1)
+		go si.blockEventsLoop()
+
+		si.wg.Add(1)
+		go si.mempoolTxsLoop()
+	})
+
+	return startErr
+}
+
+// Stop signals the indexer's loops to exit and stops the underlying
+// scanner and consumer.
+func (si *StakingIndexer) Stop() error {
+	var stopErr error
+	si.stopOnce.Do(func() {
+		close(si.quit)
+		si.wg.Wait()
+
+		if err := si.btcScanner.Stop(); err != nil {
+			stopErr = err
+			return
+		}
+
+		if err := si.consumer.Stop(); err != nil {
+			stopErr = err
+			return
+		}
+	})
+
+	return stopErr
+}
+
+// blockEventsLoop serializes confirmed-block delivery and reorg
+// notifications through a single select, so that handleConfirmedBlock
+// and Rollback - which both read and update lastProcessedHeight - can
+// never run concurrently with each other. Running them off separate
+// goroutines would otherwise race: a reorg invalidating a block could
+// be processed while that same block was still being persisted.
+func (si *StakingIndexer) blockEventsLoop() {
+	defer si.wg.Done()
+
+	for {
+		select {
+		case b, ok := <-si.btcScanner.ConfirmedBlocksChan():
+			if !ok {
+				return
+			}
+			if err := si.handleConfirmedBlock(b); err != nil {
+				si.logger.Error("failed to handle confirmed block",
+					zap.Int32("height", b.Height), zap.Error(err))
+			}
+		case height, ok := <-si.btcScanner.ReorgChan():
+			if !ok {
+				return
+			}
+			if err := si.Rollback(height); err != nil {
+				si.logger.Error("failed to roll back after reorg",
+					zap.Int32("height", height), zap.Error(err))
+			}
+		case <-si.quit:
+			return
+		}
+	}
+}
+
+func (si *StakingIndexer) handleConfirmedBlock(b *types.IndexedBlock) error {
+	params := si.paramsAtHeight(b.Height)
+
+	for _, tx := range b.Txs {
+		msgTx := tx.MsgTx()
+
+		if parsed, err := btcstaking.ParseV0StakingTx(
+			msgTx, params.Tag, params.CovenantPks, params.CovenantQuorum,
+			nil,
+		); err == nil {
+			if err := si.ProcessStakingTx(
+				msgTx, parsed, uint64(b.Height), b.Header.Timestamp,
+			); err != nil {
+				si.logger.Error("failed to process staking tx",
+					zap.String("tx_hash", msgTx.TxHash().String()), zap.Error(err))
+			}
+			continue
+		}
+
+		si.tryProcessUnbondingTx(msgTx, uint64(b.Height), b.Header.Timestamp)
+	}
+
+	atomic.StoreInt64(&si.lastProcessedHeight, int64(b.Height))
+	if err := si.is.SetLastProcessedHeight(uint64(b.Height)); err != nil {
+		return fmt.Errorf("failed to persist last processed height: %w", err)
+	}
+
+	return nil
+}
+
+// Rollback undoes every staking/unbonding record the indexer persisted
+// above height, in response to the BTC chain having reorged back to
+// height, and notifies the event consumer of the affected transactions.
+func (si *StakingIndexer) Rollback(height int32) error {
+	fromHeight := uint64(atomic.LoadInt64(&si.lastProcessedHeight))
+	toHeight := uint64(height)
+	if toHeight >= fromHeight {
+		return nil
+	}
+
+	affected, err := si.is.Rollback(fromHeight, toHeight)
+	if err != nil {
+		return fmt.Errorf("failed to roll back store: %w", err)
+	}
+
+	atomic.StoreInt64(&si.lastProcessedHeight, int64(height))
+	if err := si.is.SetLastProcessedHeight(toHeight); err != nil {
+		return fmt.Errorf("failed to persist last processed height after rollback: %w", err)
+	}
+
+	return si.consumer.PushRollbackEvent(&types.RollbackEvent{
+		Height:           height,
+		AffectedTxHashes: affected,
+	})
+}
+
+func (si *StakingIndexer) tryProcessUnbondingTx(tx *wire.MsgTx, height uint64, timestamp time.Time) {
+	if len(tx.TxIn) != 1 {
+		return
+	}
+
+	stakingTxHash := tx.TxIn[0].PreviousOutPoint.Hash
+	stakingTx, err := si.is.GetStakingTransaction(&stakingTxHash)
+	if err != nil {
+		// not a spend of a known staking output
+		return
+	}
+
+	isValid, err := si.IsValidUnbondingTx(tx, stakingTx)
+	if err != nil || !isValid {
+		return
+	}
+
+	if err := si.ProcessUnbondingTx(tx, &stakingTxHash, height, timestamp); err != nil {
+		si.logger.Error("failed to process unbonding tx",
+			zap.String("tx_hash", tx.TxHash().String()), zap.Error(err))
+	}
+}
+
+// ProcessStakingTx persists a newly observed staking transaction,
+// drops it from the pending set, then notifies the event consumer. It
+// does not validate the transaction.
+func (si *StakingIndexer) ProcessStakingTx(
+	tx *wire.MsgTx,
+	parsed *btcstaking.ParsedV0StakingTx,
+	height uint64,
+	timestamp time.Time,
+) error {
+	params := si.paramsAtHeight(int32(height))
+
+	if err := si.is.AddStakingTransaction(tx, parsed, params.Version, height, timestamp); err != nil {
+		return fmt.Errorf("failed to add staking tx to store: %w", err)
+	}
+
+	txHash := tx.TxHash()
+	if err := si.is.DeletePendingStakingTransaction(&txHash); err != nil {
+		si.logger.Error("failed to evict promoted pending staking tx",
+			zap.String("tx_hash", txHash.String()), zap.Error(err))
+	}
+
+	ev := &types.StakingEvent{
+		StakingTxHash:  tx.TxHash(),
+		StakerPk:       parsed.OpReturnData.StakerPublicKey.PubKey.SerializeCompressed(),
+		FinalityProvPk: parsed.OpReturnData.FinalityProviderPublicKey.PubKey.SerializeCompressed(),
+		StakingAmount:  parsed.StakingOutput.Value,
+		StakingTime:    uint32(parsed.OpReturnData.StakingTime),
+	}
+
+	return si.consumer.PushStakingEvent(ev)
+}
+
+// ProcessUnbondingTx persists a newly observed unbonding transaction,
+// drops it from the pending set, then notifies the event consumer. It
+// does not validate the transaction.
+func (si *StakingIndexer) ProcessUnbondingTx(
+	tx *wire.MsgTx,
+	stakingTxHash *chainhash.Hash,
+	height uint64,
+	timestamp time.Time,
+) error {
+	if err := si.is.AddUnbondingTransaction(tx, stakingTxHash, height, timestamp); err != nil {
+		return fmt.Errorf("failed to add unbonding tx to store: %w", err)
+	}
+
+	txHash := tx.TxHash()
+	if err := si.is.DeletePendingUnbondingTransaction(&txHash); err != nil {
+		si.logger.Error("failed to evict promoted pending unbonding tx",
+			zap.String("tx_hash", txHash.String()), zap.Error(err))
+	}
+
+	ev := &types.UnbondingEvent{
+		StakingTxHash:   *stakingTxHash,
+		UnbondingTxHash: tx.TxHash(),
+	}
+
+	return si.consumer.PushUnbondingEvent(ev)
+}
+
+// IsValidUnbondingTx returns true if tx is a valid unbonding transaction
+// for the given staking transaction under the params version that
+// staking transaction was indexed under. It
+// returns ErrInvalidUnbondingTx if tx looks like an attempt to unbond
+// stakingTx but fails to satisfy the params.
+func (si *StakingIndexer) IsValidUnbondingTx(
+	tx *wire.MsgTx,
+	stakingTx *indexerstore.StoredStakingTransaction,
+) (bool, error) {
+	if len(tx.TxIn) != 1 || len(tx.TxOut) != 1 {
+		return false, nil
+	}
+
+	spentOutpoint := tx.TxIn[0].PreviousOutPoint
+	if spentOutpoint.Hash != stakingTx.Tx.TxHash() ||
+		spentOutpoint.Index != stakingTx.StakingOutputIdx {
+		return false, nil
+	}
+
+	params, ok := si.paramsByVersion(stakingTx.ParamsVersion)
+	if !ok {
+		return false, fmt.Errorf("unknown params version %d for staking tx %s",
+			stakingTx.ParamsVersion, stakingTx.Tx.TxHash())
+	}
+
+	expectedValue := stakingTx.Tx.TxOut[stakingTx.StakingOutputIdx].Value - int64(params.UnbondingFee)
+	if tx.TxOut[0].Value != expectedValue {
+		return false, ErrInvalidUnbondingTx
+	}
+
+	return true, nil
+}
+
+// GetStakingTxByHash returns the staking transaction previously
+// observed and stored under txHash.
+func (si *StakingIndexer) GetStakingTxByHash(txHash *chainhash.Hash) (*indexerstore.StoredStakingTransaction, error) {
+	return si.is.GetStakingTransaction(txHash)
+}
+
+// GetUnbondingTxByHash returns the unbonding transaction previously
+// observed and stored under txHash.
+func (si *StakingIndexer) GetUnbondingTxByHash(txHash *chainhash.Hash) (*indexerstore.StoredUnbondingTransaction, error) {
+	return si.is.GetUnbondingTransaction(txHash)
+}
@@ -129,6 +129,172 @@ func FuzzIndexer(f *testing.F) {
 	})
 }
 
+// FuzzIndexerMempoolPromotion tests that a staking tx delivered only
+// via the mempool channel is not reported as a confirmed staking tx,
+// and that once the same tx is delivered as part of a confirmed block
+// it ends up queryable as a confirmed staking tx.
+func FuzzIndexerMempoolPromotion(f *testing.F) {
+	bbndatagen.AddRandomSeedsToFuzzer(f, 3)
+
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		homePath := filepath.Join(t.TempDir(), "indexer")
+		cfg := config.DefaultConfigWithHome(homePath)
+
+		confirmedBlockChan := make(chan *types.IndexedBlock)
+		mempoolTxsChan := make(chan *btcutil.Tx)
+		sysParams := datagen.GenerateGlobalParams(r, t)
+
+		db, err := cfg.DatabaseConfig.GetDbBackend()
+		require.NoError(t, err)
+		mockBtcScanner := NewMockedBtcScannerWithMempool(t, confirmedBlockChan, mempoolTxsChan)
+		stakingIndexer, err := indexer.NewStakingIndexer(cfg, zap.NewNop(), NewMockedConsumer(t), db, sysParams, mockBtcScanner)
+		require.NoError(t, err)
+
+		err = stakingIndexer.Start(1)
+		require.NoError(t, err)
+		defer func() {
+			err := stakingIndexer.Stop()
+			require.NoError(t, err)
+			err = db.Close()
+			require.NoError(t, err)
+		}()
+
+		stakingData := datagen.GenerateTestStakingData(t, r)
+		_, stakingTx := datagen.GenerateStakingTxFromTestData(t, r, sysParams, stakingData)
+		txHash := stakingTx.MsgTx().TxHash()
+
+		// 1. deliver the tx only via the mempool channel, and expect it
+		// not to be queryable as a confirmed staking tx
+		mempoolTxsChan <- stakingTx
+		require.Eventually(t, func() bool {
+			_, err := stakingIndexer.GetStakingTxByHash(&txHash)
+			return err != nil
+		}, time.Second, 10*time.Millisecond)
+
+		// 2. deliver the same tx as part of a confirmed block, and
+		// expect it to be promoted to a confirmed staking tx
+		b := &types.IndexedBlock{
+			Height: 100,
+			Txs:    []*btcutil.Tx{stakingTx},
+			Header: &wire.BlockHeader{Timestamp: time.Now()},
+		}
+		confirmedBlockChan <- b
+
+		require.Eventually(t, func() bool {
+			storedTx, err := stakingIndexer.GetStakingTxByHash(&txHash)
+			return err == nil && storedTx.Tx.TxHash() == txHash
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+// FuzzIndexerRollback tests that when the btc scanner reports a reorg
+// back to some height, every staking tx confirmed above that height is
+// rolled back out of the store while every staking tx at or below it
+// survives, and that transactions subsequently confirmed on the new
+// canonical chain are indexed as usual.
+func FuzzIndexerRollback(f *testing.F) {
+	bbndatagen.AddRandomSeedsToFuzzer(f, 3)
+
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		homePath := filepath.Join(t.TempDir(), "indexer")
+		cfg := config.DefaultConfigWithHome(homePath)
+
+		confirmedBlockChan := make(chan *types.IndexedBlock)
+		mempoolTxsChan := make(chan *btcutil.Tx)
+		reorgChan := make(chan int32)
+		sysParams := datagen.GenerateGlobalParams(r, t)
+
+		db, err := cfg.DatabaseConfig.GetDbBackend()
+		require.NoError(t, err)
+		mockBtcScanner := NewMockedBtcScannerWithReorg(t, confirmedBlockChan, mempoolTxsChan, reorgChan)
+		stakingIndexer, err := indexer.NewStakingIndexer(cfg, zap.NewNop(), NewMockedConsumer(t), db, sysParams, mockBtcScanner)
+		require.NoError(t, err)
+
+		err = stakingIndexer.Start(1)
+		require.NoError(t, err)
+		defer func() {
+			err := stakingIndexer.Stop()
+			require.NoError(t, err)
+			err = db.Close()
+			require.NoError(t, err)
+		}()
+
+		startingHeight := r.Int31n(1000) + 10
+		numBlocks := r.Intn(5) + 2
+
+		mineBlock := func(height int32) *btcutil.Tx {
+			stakingData := datagen.GenerateTestStakingData(t, r)
+			_, stakingTx := datagen.GenerateStakingTxFromTestData(t, r, sysParams, stakingData)
+			confirmedBlockChan <- &types.IndexedBlock{
+				Height: height,
+				Txs:    []*btcutil.Tx{stakingTx},
+				Header: &wire.BlockHeader{Timestamp: time.Now()},
+			}
+			return stakingTx
+		}
+
+		var keptTxs, discardedTxs []*btcutil.Tx
+		rollbackAt := r.Intn(numBlocks-1) + 1 // 1..numBlocks-1
+		for i := 0; i < numBlocks; i++ {
+			tx := mineBlock(startingHeight + int32(i))
+			if i < rollbackAt {
+				keptTxs = append(keptTxs, tx)
+			} else {
+				discardedTxs = append(discardedTxs, tx)
+			}
+		}
+
+		for _, tx := range keptTxs {
+			txHash := tx.MsgTx().TxHash()
+			require.Eventually(t, func() bool {
+				_, err := stakingIndexer.GetStakingTxByHash(&txHash)
+				return err == nil
+			}, defaultEventualTimeout, defaultEventualInterval)
+		}
+		for _, tx := range discardedTxs {
+			txHash := tx.MsgTx().TxHash()
+			require.Eventually(t, func() bool {
+				_, err := stakingIndexer.GetStakingTxByHash(&txHash)
+				return err == nil
+			}, defaultEventualTimeout, defaultEventualInterval)
+		}
+
+		rollbackHeight := startingHeight + int32(rollbackAt) - 1
+		reorgChan <- rollbackHeight
+
+		for _, tx := range discardedTxs {
+			txHash := tx.MsgTx().TxHash()
+			require.Eventually(t, func() bool {
+				_, err := stakingIndexer.GetStakingTxByHash(&txHash)
+				return err != nil
+			}, defaultEventualTimeout, defaultEventualInterval)
+		}
+		for _, tx := range keptTxs {
+			txHash := tx.MsgTx().TxHash()
+			_, err := stakingIndexer.GetStakingTxByHash(&txHash)
+			require.NoError(t, err)
+		}
+
+		// re-apply a different set of blocks on the new canonical chain
+		var newTxs []*btcutil.Tx
+		for i := rollbackAt; i < numBlocks; i++ {
+			newTxs = append(newTxs, mineBlock(startingHeight+int32(i)))
+		}
+
+		for _, tx := range newTxs {
+			txHash := tx.MsgTx().TxHash()
+			require.Eventually(t, func() bool {
+				_, err := stakingIndexer.GetStakingTxByHash(&txHash)
+				return err == nil
+			}, defaultEventualTimeout, defaultEventualInterval)
+		}
+	})
+}
+
 // FuzzVerifyUnbondingTx tests IsValidUnbondingTx in three scenarios:
 // 1. it returns (true, nil) if the given tx is valid unbonding tx
 // 2. it returns (false, nil) if the given tx is not unbonding tx
@@ -245,6 +411,9 @@ func NewMockedConsumer(t *testing.T) *mocks.MockEventConsumer {
 	mockedConsumer.EXPECT().PushStakingEvent(gomock.Any()).Return(nil).AnyTimes()
 	mockedConsumer.EXPECT().PushUnbondingEvent(gomock.Any()).Return(nil).AnyTimes()
 	mockedConsumer.EXPECT().PushWithdrawEvent(gomock.Any()).Return(nil).AnyTimes()
+	mockedConsumer.EXPECT().PushPendingStakingEvent(gomock.Any()).Return(nil).AnyTimes()
+	mockedConsumer.EXPECT().PushPendingUnbondingEvent(gomock.Any()).Return(nil).AnyTimes()
+	mockedConsumer.EXPECT().PushRollbackEvent(gomock.Any()).Return(nil).AnyTimes()
 	mockedConsumer.EXPECT().Start().Return(nil).AnyTimes()
 	mockedConsumer.EXPECT().Stop().Return(nil).AnyTimes()
 
@@ -252,10 +421,29 @@ func NewMockedConsumer(t *testing.T) *mocks.MockEventConsumer {
 }
 
 func NewMockedBtcScanner(t *testing.T, confirmedBlocksChan chan *types.IndexedBlock) *mocks.MockBtcScanner {
+	return NewMockedBtcScannerWithMempool(t, confirmedBlocksChan, make(chan *btcutil.Tx))
+}
+
+func NewMockedBtcScannerWithMempool(
+	t *testing.T,
+	confirmedBlocksChan chan *types.IndexedBlock,
+	mempoolTxsChan chan *btcutil.Tx,
+) *mocks.MockBtcScanner {
+	return NewMockedBtcScannerWithReorg(t, confirmedBlocksChan, mempoolTxsChan, make(chan int32))
+}
+
+func NewMockedBtcScannerWithReorg(
+	t *testing.T,
+	confirmedBlocksChan chan *types.IndexedBlock,
+	mempoolTxsChan chan *btcutil.Tx,
+	reorgChan chan int32,
+) *mocks.MockBtcScanner {
 	ctl := gomock.NewController(t)
 	mockBtcScanner := mocks.NewMockBtcScanner(ctl)
 	mockBtcScanner.EXPECT().Start(gomock.Any()).Return(nil).AnyTimes()
 	mockBtcScanner.EXPECT().ConfirmedBlocksChan().Return(confirmedBlocksChan).AnyTimes()
+	mockBtcScanner.EXPECT().MempoolTxsChan().Return(mempoolTxsChan).AnyTimes()
+	mockBtcScanner.EXPECT().ReorgChan().Return(reorgChan).AnyTimes()
 	mockBtcScanner.EXPECT().Stop().Return(nil).AnyTimes()
 
 	return mockBtcScanner
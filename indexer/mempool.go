@@ -0,0 +1,120 @@
+package indexer
+
+import (
+	"time"
+
+	"github.com/babylonchain/babylon/btcstaking"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/staking-indexer/types"
+)
+
+// pendingTxTTL bounds how long a staking/unbonding transaction seen
+// only in the mempool is kept around before being evicted, in case it
+// never confirms (e.g. it was replaced or dropped from the mempool).
+const pendingTxTTL = 24 * time.Hour
+
+// pendingEvictionInterval is how often the indexer sweeps for expired
+// pending transactions.
+const pendingEvictionInterval = 10 * time.Minute
+
+func (si *StakingIndexer) mempoolTxsLoop() {
+	defer si.wg.Done()
+
+	ticker := time.NewTicker(pendingEvictionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case tx, ok := <-si.btcScanner.MempoolTxsChan():
+			if !ok {
+				return
+			}
+			si.processMempoolTx(tx)
+		case <-ticker.C:
+			si.evictExpiredPending()
+		case <-si.quit:
+			return
+		}
+	}
+}
+
+func (si *StakingIndexer) processMempoolTx(tx *btcutil.Tx) {
+	msgTx := tx.MsgTx()
+	now := time.Now()
+	// A mempool tx has no confirmation height yet, so it is parsed
+	// against the params version with the highest ActivationHeight.
+	params := si.latestParams()
+
+	if parsed, err := btcstaking.ParseV0StakingTx(
+		msgTx, params.Tag, params.CovenantPks, params.CovenantQuorum, nil,
+	); err == nil {
+		if err := si.is.AddPendingStakingTransaction(msgTx, now); err != nil {
+			si.logger.Error("failed to add pending staking tx",
+				zap.String("tx_hash", msgTx.TxHash().String()), zap.Error(err))
+			return
+		}
+
+		ev := &types.PendingStakingEvent{
+			StakingTxHash:  msgTx.TxHash(),
+			StakerPk:       parsed.OpReturnData.StakerPublicKey.PubKey.SerializeCompressed(),
+			FinalityProvPk: parsed.OpReturnData.FinalityProviderPublicKey.PubKey.SerializeCompressed(),
+			StakingAmount:  parsed.StakingOutput.Value,
+			StakingTime:    uint32(parsed.OpReturnData.StakingTime),
+		}
+		if err := si.consumer.PushPendingStakingEvent(ev); err != nil {
+			si.logger.Error("failed to push pending staking event", zap.Error(err))
+		}
+		return
+	}
+
+	si.tryProcessMempoolUnbondingTx(msgTx, now)
+}
+
+func (si *StakingIndexer) tryProcessMempoolUnbondingTx(tx *wire.MsgTx, now time.Time) {
+	if len(tx.TxIn) != 1 {
+		return
+	}
+
+	stakingTxHash := tx.TxIn[0].PreviousOutPoint.Hash
+	stakingTx, err := si.is.GetStakingTransaction(&stakingTxHash)
+	if err != nil {
+		// not a spend of a known staking output
+		return
+	}
+
+	isValid, err := si.IsValidUnbondingTx(tx, stakingTx)
+	if err != nil || !isValid {
+		return
+	}
+
+	if err := si.is.AddPendingUnbondingTransaction(tx, &stakingTxHash, now); err != nil {
+		si.logger.Error("failed to add pending unbonding tx",
+			zap.String("tx_hash", tx.TxHash().String()), zap.Error(err))
+		return
+	}
+
+	ev := &types.PendingUnbondingEvent{
+		StakingTxHash:   stakingTxHash,
+		UnbondingTxHash: tx.TxHash(),
+	}
+	if err := si.consumer.PushPendingUnbondingEvent(ev); err != nil {
+		si.logger.Error("failed to push pending unbonding event", zap.Error(err))
+	}
+}
+
+func (si *StakingIndexer) evictExpiredPending() {
+	if expired, err := si.is.EvictExpiredPendingStakingTransactions(pendingTxTTL); err != nil {
+		si.logger.Error("failed to evict expired pending staking txs", zap.Error(err))
+	} else if len(expired) > 0 {
+		si.logger.Debug("evicted expired pending staking txs", zap.Int("count", len(expired)))
+	}
+
+	if expired, err := si.is.EvictExpiredPendingUnbondingTransactions(pendingTxTTL); err != nil {
+		si.logger.Error("failed to evict expired pending unbonding txs", zap.Error(err))
+	} else if len(expired) > 0 {
+		si.logger.Debug("evicted expired pending unbonding txs", zap.Int("count", len(expired)))
+	}
+}
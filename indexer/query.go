@@ -0,0 +1,112 @@
+package indexer
+
+import (
+	"sync/atomic"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+
+	"github.com/babylonchain/staking-indexer/indexerstore"
+)
+
+// IndexerStatus summarizes the indexer's progress, for consumers that
+// want to know how far behind the BTC tip it currently is.
+type IndexerStatus struct {
+	LastProcessedHeight uint64
+	ParamsVersion       uint32
+}
+
+// ListStakesByStakerPk returns every staking transaction observed for
+// the given staker public key.
+func (si *StakingIndexer) ListStakesByStakerPk(pk *btcec.PublicKey) ([]*indexerstore.StoredStakingTransaction, error) {
+	hashes, err := si.is.GetStakingTxHashesByStakerPk(pk)
+	if err != nil {
+		return nil, err
+	}
+
+	return si.resolveStakingTxs(hashes)
+}
+
+// ListStakesByFinalityProviderPk returns every staking transaction
+// observed for the given finality provider public key.
+func (si *StakingIndexer) ListStakesByFinalityProviderPk(pk *btcec.PublicKey) ([]*indexerstore.StoredStakingTransaction, error) {
+	hashes, err := si.is.GetStakingTxHashesByFinalityProviderPk(pk)
+	if err != nil {
+		return nil, err
+	}
+
+	return si.resolveStakingTxs(hashes)
+}
+
+// ListActiveStakesAtHeight returns every staking transaction included
+// at or before the given BTC height that had not been unbonded at or
+// before that height.
+func (si *StakingIndexer) ListActiveStakesAtHeight(height uint64) ([]*indexerstore.StoredStakingTransaction, error) {
+	hashes, err := si.is.GetStakingTxHashesByHeightRange(0, height)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]*indexerstore.StoredStakingTransaction, 0, len(hashes))
+	for _, h := range hashes {
+		stakingTx, err := si.is.GetStakingTransaction(&h)
+		if err != nil {
+			return nil, err
+		}
+
+		if unbondingTxHash, err := si.is.GetUnbondingTxHashByStakingTxHash(&h); err == nil {
+			unbondingTx, err := si.is.GetUnbondingTransaction(unbondingTxHash)
+			if err != nil {
+				return nil, err
+			}
+			if unbondingTx.InclusionHeight <= height {
+				// already unbonded at height
+				continue
+			}
+		}
+
+		active = append(active, stakingTx)
+	}
+
+	return active, nil
+}
+
+// ListUnbondingsByStakingTxHash returns the unbonding transaction
+// spending the staking transaction identified by stakingTxHash, if
+// one has been observed.
+func (si *StakingIndexer) ListUnbondingsByStakingTxHash(
+	stakingTxHash *chainhash.Hash,
+) (*indexerstore.StoredUnbondingTransaction, error) {
+	unbondingTxHash, err := si.is.GetUnbondingTxHashByStakingTxHash(stakingTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return si.is.GetUnbondingTransaction(unbondingTxHash)
+}
+
+// GetIndexerStatus returns a snapshot of the indexer's current
+// progress.
+func (si *StakingIndexer) GetIndexerStatus() *IndexerStatus {
+	lastProcessedHeight := atomic.LoadInt64(&si.lastProcessedHeight)
+
+	return &IndexerStatus{
+		LastProcessedHeight: uint64(lastProcessedHeight),
+		ParamsVersion:       si.paramsAtHeight(int32(lastProcessedHeight)).Version,
+	}
+}
+
+func (si *StakingIndexer) resolveStakingTxs(
+	hashes []chainhash.Hash,
+) ([]*indexerstore.StoredStakingTransaction, error) {
+	txs := make([]*indexerstore.StoredStakingTransaction, 0, len(hashes))
+	for _, h := range hashes {
+		tx, err := si.is.GetStakingTransaction(&h)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+
+	return txs, nil
+}
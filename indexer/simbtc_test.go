@@ -0,0 +1,206 @@
+package indexer_test
+
+import (
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/staking-indexer/config"
+	"github.com/babylonchain/staking-indexer/indexer"
+	"github.com/babylonchain/staking-indexer/testutils"
+	"github.com/babylonchain/staking-indexer/testutils/datagen"
+	"github.com/babylonchain/staking-indexer/testutils/simbtc"
+)
+
+const (
+	defaultEventualTimeout  = time.Second
+	defaultEventualInterval = 10 * time.Millisecond
+)
+
+// TestIndexerReorgKeepsOnlyCanonicalInclusion mines a staking tx,
+// reorgs it out before it reaches the scanner's confirmation depth,
+// re-mines it at a different height, and asserts the indexer's store
+// reflects only the canonical inclusion.
+func TestIndexerReorgKeepsOnlyCanonicalInclusion(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	const confirmationDepth = 3
+	const startHeight = int32(100)
+
+	homePath := filepath.Join(t.TempDir(), "indexer")
+	cfg := config.DefaultConfigWithHome(homePath)
+
+	db, err := cfg.DatabaseConfig.GetDbBackend()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, db.Close()) }()
+
+	sysParams := datagen.GenerateGlobalParams(r, t)
+	chain := simbtc.NewSimulatedBtcChain(startHeight, confirmationDepth)
+
+	stakingIndexer, err := indexer.NewStakingIndexer(cfg, zap.NewNop(), NewMockedConsumer(t), db, sysParams, chain)
+	require.NoError(t, err)
+	require.NoError(t, stakingIndexer.Start(startHeight))
+	defer func() { require.NoError(t, stakingIndexer.Stop()) }()
+
+	stakingData := datagen.GenerateTestStakingData(t, r)
+	_, stakingTx := datagen.GenerateStakingTxFromTestData(t, r, sysParams, stakingData)
+	txHash := stakingTx.MsgTx().TxHash()
+
+	// mine the staking tx, then one more block on top of it: still
+	// short of confirmationDepth, so the indexer hasn't seen it yet.
+	reorgedHeight := chain.Mine(stakingTx)
+	chain.Mine()
+	_, err = stakingIndexer.GetStakingTxByHash(&txHash)
+	require.Error(t, err)
+
+	// reorg out both unconfirmed blocks, replacing them with two empty
+	// blocks that do not contain the staking tx.
+	require.NoError(t, chain.Reorg(2, nil, nil))
+
+	// re-mine the staking tx at a new height, then push it past the
+	// confirmation depth.
+	canonicalHeight := chain.Mine(stakingTx)
+	require.NotEqual(t, reorgedHeight, canonicalHeight)
+	chain.Mine()
+	chain.Mine()
+
+	require.Eventually(t, func() bool {
+		storedTx, err := stakingIndexer.GetStakingTxByHash(&txHash)
+		return err == nil && storedTx.InclusionHeight == uint64(canonicalHeight)
+	}, defaultEventualTimeout, defaultEventualInterval)
+}
+
+// TestIndexerDeepReorgRollsBackConfirmedTxs mines and confirms two
+// staking txs, then exercises SimulatedBtcChain.DeepReorg to discard
+// the block holding the second one - as a real reorg below the
+// indexer's already-processed tip would, via ReorgChan - replacing it
+// with an empty block. It asserts the indexer rolls the discarded tx
+// back out of its store while leaving the earlier, still-canonical
+// one untouched.
+func TestIndexerDeepReorgRollsBackConfirmedTxs(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+
+	const confirmationDepth = 1
+	const startHeight = int32(300)
+
+	homePath := filepath.Join(t.TempDir(), "indexer")
+	cfg := config.DefaultConfigWithHome(homePath)
+
+	db, err := cfg.DatabaseConfig.GetDbBackend()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, db.Close()) }()
+
+	sysParams := datagen.GenerateGlobalParams(r, t)
+	chain := simbtc.NewSimulatedBtcChain(startHeight, confirmationDepth)
+
+	stakingIndexer, err := indexer.NewStakingIndexer(cfg, zap.NewNop(), NewMockedConsumer(t), db, sysParams, chain)
+	require.NoError(t, err)
+	require.NoError(t, stakingIndexer.Start(startHeight))
+	defer func() { require.NoError(t, stakingIndexer.Stop()) }()
+
+	keptData := datagen.GenerateTestStakingData(t, r)
+	_, keptTx := datagen.GenerateStakingTxFromTestData(t, r, sysParams, keptData)
+	keptHash := keptTx.MsgTx().TxHash()
+	keptHeight := chain.Mine(keptTx)
+
+	discardedData := datagen.GenerateTestStakingData(t, r)
+	_, discardedTx := datagen.GenerateStakingTxFromTestData(t, r, sysParams, discardedData)
+	discardedHash := discardedTx.MsgTx().TxHash()
+	chain.Mine(discardedTx)
+
+	require.Eventually(t, func() bool {
+		_, err := stakingIndexer.GetStakingTxByHash(&keptHash)
+		return err == nil
+	}, defaultEventualTimeout, defaultEventualInterval)
+	require.Eventually(t, func() bool {
+		_, err := stakingIndexer.GetStakingTxByHash(&discardedHash)
+		return err == nil
+	}, defaultEventualTimeout, defaultEventualInterval)
+
+	// a deep reorg discards the block holding discardedTx, replacing
+	// it with a single empty block.
+	require.NoError(t, chain.DeepReorg(keptHeight, nil))
+
+	require.Eventually(t, func() bool {
+		_, err := stakingIndexer.GetStakingTxByHash(&discardedHash)
+		return err != nil
+	}, defaultEventualTimeout, defaultEventualInterval)
+
+	storedKept, err := stakingIndexer.GetStakingTxByHash(&keptHash)
+	require.NoError(t, err)
+	require.Equal(t, uint64(keptHeight), storedKept.InclusionHeight)
+}
+
+// TestIndexerParamsVersionBoundary runs a single StakingIndexer
+// instance across a params-version boundary: the indexer is started
+// knowing only paramsV0, paramsV1 is registered via AddParams ahead of
+// its ActivationHeight, and the chain is mined through the boundary.
+// It asserts that a staking tx confirmed below the boundary is
+// indexed under paramsV0's version and one confirmed at or above it
+// is indexed under paramsV1's version, i.e. the indexer itself
+// selects the active version from a block's height rather than being
+// pinned to whatever params it started with.
+func TestIndexerParamsVersionBoundary(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	const confirmationDepth = 1
+	const startHeight = int32(200)
+	const boundaryHeight = int32(210)
+
+	homePath := filepath.Join(t.TempDir(), "indexer")
+	cfg := config.DefaultConfigWithHome(homePath)
+
+	db, err := cfg.DatabaseConfig.GetDbBackend()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, db.Close()) }()
+
+	paramsV0 := datagen.GenerateGlobalParams(r, t)
+	paramsV0.Version = 0
+	paramsV0.ActivationHeight = startHeight
+
+	paramsV1 := datagen.GenerateGlobalParams(r, t)
+	paramsV1.Version = 1
+	paramsV1.ActivationHeight = boundaryHeight
+
+	chain := simbtc.NewSimulatedBtcChain(startHeight, confirmationDepth)
+
+	stakingIndexer, err := indexer.NewStakingIndexer(cfg, zap.NewNop(), NewMockedConsumer(t), db, paramsV0, chain)
+	require.NoError(t, err)
+	require.NoError(t, stakingIndexer.AddParams(paramsV1))
+	require.NoError(t, stakingIndexer.Start(startHeight))
+	defer func() { require.NoError(t, stakingIndexer.Stop()) }()
+
+	dataV0 := datagen.GenerateTestStakingData(t, r)
+	_, stakingTxV0 := datagen.GenerateStakingTxFromTestData(t, r, paramsV0, dataV0)
+	txHashV0 := stakingTxV0.MsgTx().TxHash()
+
+	for chain.TipHeight() < boundaryHeight-2 {
+		chain.Mine()
+	}
+	chain.Mine(stakingTxV0)
+
+	require.Eventually(t, func() bool {
+		storedTx, err := stakingIndexer.GetStakingTxByHash(&txHashV0)
+		return err == nil && storedTx.ParamsVersion == paramsV0.Version
+	}, defaultEventualTimeout, defaultEventualInterval)
+
+	dataV1 := datagen.GenerateTestStakingData(t, r)
+	_, stakingTxV1 := datagen.GenerateStakingTxFromTestData(t, r, paramsV1, dataV1)
+	txHashV1 := stakingTxV1.MsgTx().TxHash()
+
+	require.Equal(t, boundaryHeight, chain.Mine(stakingTxV1))
+
+	require.Eventually(t, func() bool {
+		storedTx, err := stakingIndexer.GetStakingTxByHash(&txHashV1)
+		return err == nil &&
+			storedTx.ParamsVersion == paramsV1.Version &&
+			testutils.PubKeysEqual(storedTx.StakerPk, dataV1.StakerKey)
+	}, defaultEventualTimeout, defaultEventualInterval)
+
+	status := stakingIndexer.GetIndexerStatus()
+	require.Equal(t, paramsV1.Version, status.ParamsVersion)
+}
@@ -0,0 +1,155 @@
+package indexerstore
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// OpKind identifies what kind of mutation a changelog entry records.
+type OpKind uint8
+
+const (
+	// OpAddStake records that a staking transaction was added.
+	OpAddStake OpKind = iota
+	// OpAddUnbond records that an unbonding transaction was added.
+	OpAddUnbond
+)
+
+// opRecord is a single changelog entry: a mutation of kind applied to
+// the transaction identified by txHash.
+type opRecord struct {
+	kind   OpKind
+	txHash chainhash.Hash
+}
+
+// appendChangelog records that an op of the given kind was applied to
+// txHash while processing height, so Rollback can later invert it.
+func appendChangelog(bucket kvdb.RwBucket, height uint64, txHash chainhash.Hash, kind OpKind) error {
+	heightBucket, err := bucket.CreateBucketIfNotExists(heightKey(height))
+	if err != nil {
+		return err
+	}
+
+	return heightBucket.Put(txHash[:], []byte{byte(kind)})
+}
+
+// Rollback walks the changelog from fromHeight down to, but not
+// including, toHeight, inverting every recorded op: staking/unbonding
+// records and their secondary indexes are deleted, and the changelog
+// entries themselves are consumed. It returns the hashes of every
+// transaction affected, in the order they were rolled back.
+func (s *IndexerStore) Rollback(fromHeight, toHeight uint64) ([]chainhash.Hash, error) {
+	var affected []chainhash.Hash
+
+	err := kvdb.Update(s.db, func(dbTx kvdb.RwTx) error {
+		changelog := dbTx.ReadWriteBucket(changelogBucketName)
+		if changelog == nil {
+			return fmt.Errorf("changelog bucket not found")
+		}
+		stakingBucket := dbTx.ReadWriteBucket(stakingTxBucketName)
+		unbondingBucket := dbTx.ReadWriteBucket(unbondingTxBucketName)
+		stakerIndex := dbTx.ReadWriteBucket(stakerIndexBucketName)
+		fpIndex := dbTx.ReadWriteBucket(fpIndexBucketName)
+		heightIndex := dbTx.ReadWriteBucket(heightIndexBucketName)
+		byStaking := dbTx.ReadWriteBucket(unbondingByStakingIndexBucketName)
+
+		for h := fromHeight; h > toHeight; h-- {
+			heightLog := changelog.NestedReadWriteBucket(heightKey(h))
+			if heightLog == nil {
+				continue
+			}
+
+			ops, err := readOpsAndClear(heightLog)
+			if err != nil {
+				return err
+			}
+
+			for _, op := range ops {
+				switch op.kind {
+				case OpAddStake:
+					if err := invertAddStake(stakingBucket, stakerIndex, fpIndex, heightIndex, op.txHash); err != nil {
+						return err
+					}
+				case OpAddUnbond:
+					if err := invertAddUnbond(unbondingBucket, byStaking, op.txHash); err != nil {
+						return err
+					}
+				}
+				affected = append(affected, op.txHash)
+			}
+
+			if err := changelog.DeleteNestedBucket(heightKey(h)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() { affected = nil })
+	if err != nil {
+		return nil, err
+	}
+
+	return affected, nil
+}
+
+func readOpsAndClear(heightLog kvdb.RwBucket) ([]opRecord, error) {
+	var ops []opRecord
+
+	err := heightLog.ForEach(func(k, v []byte) error {
+		hash, err := chainhash.NewHash(k)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, opRecord{kind: OpKind(v[0]), txHash: *hash})
+		return nil
+	})
+
+	return ops, err
+}
+
+func invertAddStake(
+	stakingBucket, stakerIndex, fpIndex, heightIndex kvdb.RwBucket,
+	txHash chainhash.Hash,
+) error {
+	raw := stakingBucket.Get(txHash[:])
+	if raw == nil {
+		return nil
+	}
+
+	stored, err := unmarshalStoredStakingTx(raw)
+	if err != nil {
+		return err
+	}
+
+	if err := removePkIndex(stakerIndex, stored.StakerPk, txHash); err != nil {
+		return err
+	}
+	if err := removePkIndex(fpIndex, stored.FinalityProviderPk, txHash); err != nil {
+		return err
+	}
+	if err := removeHeightIndex(heightIndex, stored.InclusionHeight, txHash); err != nil {
+		return err
+	}
+
+	return stakingBucket.Delete(txHash[:])
+}
+
+func invertAddUnbond(unbondingBucket, byStaking kvdb.RwBucket, txHash chainhash.Hash) error {
+	raw := unbondingBucket.Get(txHash[:])
+	if raw == nil {
+		return nil
+	}
+
+	stored, err := unmarshalStoredUnbondingTx(raw)
+	if err != nil {
+		return err
+	}
+
+	if err := byStaking.Delete(stored.StakingTxHash[:]); err != nil {
+		return err
+	}
+
+	return unbondingBucket.Delete(txHash[:])
+}
@@ -0,0 +1,146 @@
+package indexerstore
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+const chainhashLen = chainhash.HashSize
+
+func addPkIndex(bucket kvdb.RwBucket, pk *btcec.PublicKey, txHash chainhash.Hash) error {
+	key := pk.SerializeCompressed()
+
+	existing := bucket.Get(key)
+	for i := 0; i+chainhashLen <= len(existing); i += chainhashLen {
+		if bytesEqual(existing[i:i+chainhashLen], txHash[:]) {
+			// already indexed
+			return nil
+		}
+	}
+
+	return bucket.Put(key, append(existing, txHash[:]...))
+}
+
+func getPkIndex(bucket kvdb.RBucket, pk *btcec.PublicKey) []chainhash.Hash {
+	raw := bucket.Get(pk.SerializeCompressed())
+	return splitHashes(raw)
+}
+
+// removePkIndex removes txHash from pk's list of indexed hashes.
+func removePkIndex(bucket kvdb.RwBucket, pk *btcec.PublicKey, txHash chainhash.Hash) error {
+	key := pk.SerializeCompressed()
+	hashes := splitHashes(bucket.Get(key))
+
+	remaining := make([]byte, 0, len(hashes)*chainhashLen)
+	for _, h := range hashes {
+		if h == txHash {
+			continue
+		}
+		remaining = append(remaining, h[:]...)
+	}
+
+	if len(remaining) == 0 {
+		return bucket.Delete(key)
+	}
+
+	return bucket.Put(key, remaining)
+}
+
+func addHeightIndex(bucket kvdb.RwBucket, height uint64, txHash chainhash.Hash) error {
+	heightBucket, err := bucket.CreateBucketIfNotExists(heightKey(height))
+	if err != nil {
+		return err
+	}
+
+	return heightBucket.Put(txHash[:], []byte{1})
+}
+
+// removeHeightIndex removes txHash from the given height's bucket,
+// dropping the height's bucket entirely if it becomes empty.
+func removeHeightIndex(bucket kvdb.RwBucket, height uint64, txHash chainhash.Hash) error {
+	heightBucket := bucket.NestedReadWriteBucket(heightKey(height))
+	if heightBucket == nil {
+		return nil
+	}
+
+	if err := heightBucket.Delete(txHash[:]); err != nil {
+		return err
+	}
+
+	empty := true
+	if err := heightBucket.ForEach(func(_, _ []byte) error {
+		empty = false
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if empty {
+		return bucket.DeleteNestedBucket(heightKey(height))
+	}
+
+	return nil
+}
+
+// getHeightRangeIndex returns every tx hash indexed under a height in
+// [fromHeight, toHeight]. It seeks a cursor directly to the first
+// populated height sub-bucket at or above fromHeight and walks only
+// the sub-buckets that actually exist, rather than probing every
+// integer height in the range.
+func getHeightRangeIndex(bucket kvdb.RBucket, fromHeight, toHeight uint64) ([]chainhash.Hash, error) {
+	var hashes []chainhash.Hash
+
+	toKey := heightKey(toHeight)
+	cursor := bucket.ReadCursor()
+	for k, _ := cursor.Seek(heightKey(fromHeight)); k != nil && bytes.Compare(k, toKey) <= 0; k, _ = cursor.Next() {
+		heightBucket := bucket.NestedReadBucket(k)
+		if heightBucket == nil {
+			continue
+		}
+
+		if err := heightBucket.ForEach(func(hk, _ []byte) error {
+			hash, err := chainhash.NewHash(hk)
+			if err != nil {
+				return err
+			}
+			hashes = append(hashes, *hash)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return hashes, nil
+}
+
+func heightKey(height uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, height)
+	return b
+}
+
+func splitHashes(raw []byte) []chainhash.Hash {
+	hashes := make([]chainhash.Hash, 0, len(raw)/chainhashLen)
+	for i := 0; i+chainhashLen <= len(raw); i += chainhashLen {
+		var h chainhash.Hash
+		copy(h[:], raw[i:i+chainhashLen])
+		hashes = append(hashes, h)
+	}
+	return hashes
+}
+
+func bytesEqual(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package indexerstore
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// SetLastProcessedHeight persists the BTC height of the last
+// confirmed block the indexer has finished processing.
+func (s *IndexerStore) SetLastProcessedHeight(height uint64) error {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, height)
+
+	return kvdb.Update(s.db, func(dbTx kvdb.RwTx) error {
+		bucket := dbTx.ReadWriteBucket(metaBucketName)
+		if bucket == nil {
+			return fmt.Errorf("meta bucket not found")
+		}
+		return bucket.Put(lastProcessedHeightKey, b)
+	}, func() {})
+}
+
+// GetLastProcessedHeight returns the last persisted processed height,
+// and false if none has been recorded yet.
+func (s *IndexerStore) GetLastProcessedHeight() (uint64, bool, error) {
+	var height uint64
+	var found bool
+
+	err := kvdb.View(s.db, func(dbTx kvdb.RTx) error {
+		bucket := dbTx.ReadBucket(metaBucketName)
+		if bucket == nil {
+			return fmt.Errorf("meta bucket not found")
+		}
+
+		raw := bucket.Get(lastProcessedHeightKey)
+		if raw == nil {
+			return nil
+		}
+
+		height = binary.BigEndian.Uint64(raw)
+		found = true
+		return nil
+	}, func() {})
+	if err != nil {
+		return 0, false, err
+	}
+
+	return height, found, nil
+}
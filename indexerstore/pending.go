@@ -0,0 +1,202 @@
+package indexerstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// StoredPendingTransaction is a staking or unbonding transaction seen
+// in the mempool, held until it either confirms (and is promoted to a
+// StoredStakingTransaction/StoredUnbondingTransaction) or expires.
+type StoredPendingTransaction struct {
+	Tx            *wire.MsgTx
+	StakingTxHash *chainhash.Hash // nil for a pending staking tx
+	FirstSeenTime time.Time
+}
+
+// AddPendingStakingTransaction records a staking transaction observed
+// in the mempool.
+func (s *IndexerStore) AddPendingStakingTransaction(tx *wire.MsgTx, firstSeen time.Time) error {
+	return s.putPending(pendingStakingTxBucketName, tx, nil, firstSeen)
+}
+
+// AddPendingUnbondingTransaction records an unbonding transaction,
+// spending stakingTxHash, observed in the mempool.
+func (s *IndexerStore) AddPendingUnbondingTransaction(
+	tx *wire.MsgTx,
+	stakingTxHash *chainhash.Hash,
+	firstSeen time.Time,
+) error {
+	return s.putPending(pendingUnbondingTxBucketName, tx, stakingTxHash, firstSeen)
+}
+
+func (s *IndexerStore) putPending(
+	bucketName []byte,
+	tx *wire.MsgTx,
+	stakingTxHash *chainhash.Hash,
+	firstSeen time.Time,
+) error {
+	txHash := tx.TxHash()
+
+	marshalled, err := marshalStoredPendingTx(&StoredPendingTransaction{
+		Tx:            tx,
+		StakingTxHash: stakingTxHash,
+		FirstSeenTime: firstSeen,
+	})
+	if err != nil {
+		return err
+	}
+
+	return kvdb.Update(s.db, func(dbTx kvdb.RwTx) error {
+		bucket := dbTx.ReadWriteBucket(bucketName)
+		if bucket == nil {
+			return fmt.Errorf("pending tx bucket not found")
+		}
+		return bucket.Put(txHash[:], marshalled)
+	}, func() {})
+}
+
+// DeletePendingStakingTransaction removes a pending staking
+// transaction, e.g. once it has confirmed.
+func (s *IndexerStore) DeletePendingStakingTransaction(hash *chainhash.Hash) error {
+	return s.deletePending(pendingStakingTxBucketName, hash)
+}
+
+// DeletePendingUnbondingTransaction removes a pending unbonding
+// transaction, e.g. once it has confirmed.
+func (s *IndexerStore) DeletePendingUnbondingTransaction(hash *chainhash.Hash) error {
+	return s.deletePending(pendingUnbondingTxBucketName, hash)
+}
+
+func (s *IndexerStore) deletePending(bucketName []byte, hash *chainhash.Hash) error {
+	return kvdb.Update(s.db, func(dbTx kvdb.RwTx) error {
+		bucket := dbTx.ReadWriteBucket(bucketName)
+		if bucket == nil {
+			return fmt.Errorf("pending tx bucket not found")
+		}
+		return bucket.Delete(hash[:])
+	}, func() {})
+}
+
+// EvictExpiredPendingStakingTransactions deletes and returns the
+// hashes of all pending staking transactions first seen more than ttl
+// ago.
+func (s *IndexerStore) EvictExpiredPendingStakingTransactions(ttl time.Duration) ([]chainhash.Hash, error) {
+	return s.evictExpiredPending(pendingStakingTxBucketName, ttl)
+}
+
+// EvictExpiredPendingUnbondingTransactions deletes and returns the
+// hashes of all pending unbonding transactions first seen more than
+// ttl ago.
+func (s *IndexerStore) EvictExpiredPendingUnbondingTransactions(ttl time.Duration) ([]chainhash.Hash, error) {
+	return s.evictExpiredPending(pendingUnbondingTxBucketName, ttl)
+}
+
+func (s *IndexerStore) evictExpiredPending(bucketName []byte, ttl time.Duration) ([]chainhash.Hash, error) {
+	var expired []chainhash.Hash
+
+	err := kvdb.Update(s.db, func(dbTx kvdb.RwTx) error {
+		bucket := dbTx.ReadWriteBucket(bucketName)
+		if bucket == nil {
+			return fmt.Errorf("pending tx bucket not found")
+		}
+
+		var toDelete [][]byte
+		if err := bucket.ForEach(func(k, v []byte) error {
+			stored, err := unmarshalStoredPendingTx(v)
+			if err != nil {
+				return err
+			}
+			if time.Since(stored.FirstSeenTime) > ttl {
+				toDelete = append(toDelete, append([]byte{}, k...))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		for _, k := range toDelete {
+			hash, err := chainhash.NewHash(k)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+			expired = append(expired, *hash)
+		}
+
+		return nil
+	}, func() {})
+
+	return expired, err
+}
+
+func marshalStoredPendingTx(s *StoredPendingTransaction) ([]byte, error) {
+	var buf bytes.Buffer
+
+	txBytes, err := serializeTx(s.Tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeBytes(&buf, txBytes); err != nil {
+		return nil, err
+	}
+
+	stakingTxHashBytes := []byte{}
+	if s.StakingTxHash != nil {
+		stakingTxHashBytes = s.StakingTxHash[:]
+	}
+	if err := writeBytes(&buf, stakingTxHashBytes); err != nil {
+		return nil, err
+	}
+
+	if err := binary.Write(&buf, binary.BigEndian, s.FirstSeenTime.Unix()); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func unmarshalStoredPendingTx(raw []byte) (*StoredPendingTransaction, error) {
+	r := bytes.NewReader(raw)
+
+	txBytes, err := readBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := deserializeTx(txBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	stakingTxHashBytes, err := readBytes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var stakingTxHash *chainhash.Hash
+	if len(stakingTxHashBytes) > 0 {
+		stakingTxHash, err = chainhash.NewHash(stakingTxHashBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var firstSeenUnix int64
+	if err := binary.Read(r, binary.BigEndian, &firstSeenUnix); err != nil {
+		return nil, err
+	}
+
+	return &StoredPendingTransaction{
+		Tx:            tx,
+		StakingTxHash: stakingTxHash,
+		FirstSeenTime: time.Unix(firstSeenUnix, 0),
+	}, nil
+}
@@ -0,0 +1,279 @@
+package indexerstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/babylonchain/babylon/btcstaking"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// StoredStakingTransaction is the representation of a staking
+// transaction as persisted by the indexer.
+type StoredStakingTransaction struct {
+	Tx                 *wire.MsgTx
+	StakingOutputIdx   uint32
+	StakerPk           *btcec.PublicKey
+	FinalityProviderPk *btcec.PublicKey
+	StakingTime        uint32
+	ParamsVersion      uint32
+	InclusionHeight    uint64
+	InclusionTime      time.Time
+}
+
+// AddStakingTransaction persists a newly observed staking transaction.
+func (s *IndexerStore) AddStakingTransaction(
+	tx *wire.MsgTx,
+	parsed *btcstaking.ParsedV0StakingTx,
+	paramsVersion uint32,
+	inclusionHeight uint64,
+	inclusionTime time.Time,
+) error {
+	txHash := tx.TxHash()
+
+	stored := &StoredStakingTransaction{
+		Tx:                 tx,
+		StakingOutputIdx:   uint32(parsed.StakingOutputIdx),
+		StakerPk:           parsed.OpReturnData.StakerPublicKey.PubKey,
+		FinalityProviderPk: parsed.OpReturnData.FinalityProviderPublicKey.PubKey,
+		StakingTime:        uint32(parsed.OpReturnData.StakingTime),
+		ParamsVersion:      paramsVersion,
+		InclusionHeight:    inclusionHeight,
+		InclusionTime:      inclusionTime,
+	}
+
+	marshalled, err := marshalStoredStakingTx(stored)
+	if err != nil {
+		return err
+	}
+
+	return kvdb.Update(s.db, func(dbTx kvdb.RwTx) error {
+		bucket := dbTx.ReadWriteBucket(stakingTxBucketName)
+		if bucket == nil {
+			return fmt.Errorf("staking tx bucket not found")
+		}
+		if err := bucket.Put(txHash[:], marshalled); err != nil {
+			return err
+		}
+
+		stakerIndex := dbTx.ReadWriteBucket(stakerIndexBucketName)
+		if stakerIndex == nil {
+			return fmt.Errorf("staker index bucket not found")
+		}
+		if err := addPkIndex(stakerIndex, stored.StakerPk, txHash); err != nil {
+			return err
+		}
+
+		fpIndex := dbTx.ReadWriteBucket(fpIndexBucketName)
+		if fpIndex == nil {
+			return fmt.Errorf("finality provider index bucket not found")
+		}
+		if err := addPkIndex(fpIndex, stored.FinalityProviderPk, txHash); err != nil {
+			return err
+		}
+
+		heightIndex := dbTx.ReadWriteBucket(heightIndexBucketName)
+		if heightIndex == nil {
+			return fmt.Errorf("height index bucket not found")
+		}
+		if err := addHeightIndex(heightIndex, inclusionHeight, txHash); err != nil {
+			return err
+		}
+
+		changelog := dbTx.ReadWriteBucket(changelogBucketName)
+		if changelog == nil {
+			return fmt.Errorf("changelog bucket not found")
+		}
+		return appendChangelog(changelog, inclusionHeight, txHash, OpAddStake)
+	}, func() {})
+}
+
+// GetStakingTxHashesByStakerPk returns the hashes of all staking
+// transactions observed for the given staker public key.
+func (s *IndexerStore) GetStakingTxHashesByStakerPk(pk *btcec.PublicKey) ([]chainhash.Hash, error) {
+	var hashes []chainhash.Hash
+
+	err := kvdb.View(s.db, func(dbTx kvdb.RTx) error {
+		bucket := dbTx.ReadBucket(stakerIndexBucketName)
+		if bucket == nil {
+			return fmt.Errorf("staker index bucket not found")
+		}
+		hashes = getPkIndex(bucket, pk)
+		return nil
+	}, func() {})
+
+	return hashes, err
+}
+
+// GetStakingTxHashesByFinalityProviderPk returns the hashes of all
+// staking transactions observed for the given finality provider public
+// key.
+func (s *IndexerStore) GetStakingTxHashesByFinalityProviderPk(pk *btcec.PublicKey) ([]chainhash.Hash, error) {
+	var hashes []chainhash.Hash
+
+	err := kvdb.View(s.db, func(dbTx kvdb.RTx) error {
+		bucket := dbTx.ReadBucket(fpIndexBucketName)
+		if bucket == nil {
+			return fmt.Errorf("finality provider index bucket not found")
+		}
+		hashes = getPkIndex(bucket, pk)
+		return nil
+	}, func() {})
+
+	return hashes, err
+}
+
+// GetStakingTxHashesByHeight returns the hashes of all staking
+// transactions included at the given BTC height.
+func (s *IndexerStore) GetStakingTxHashesByHeight(height uint64) ([]chainhash.Hash, error) {
+	return s.GetStakingTxHashesByHeightRange(height, height)
+}
+
+// GetStakingTxHashesByHeightRange returns the hashes of all staking
+// transactions included in [fromHeight, toHeight].
+func (s *IndexerStore) GetStakingTxHashesByHeightRange(fromHeight, toHeight uint64) ([]chainhash.Hash, error) {
+	var hashes []chainhash.Hash
+
+	err := kvdb.View(s.db, func(dbTx kvdb.RTx) error {
+		bucket := dbTx.ReadBucket(heightIndexBucketName)
+		if bucket == nil {
+			return fmt.Errorf("height index bucket not found")
+		}
+
+		var err error
+		hashes, err = getHeightRangeIndex(bucket, fromHeight, toHeight)
+		return err
+	}, func() {})
+
+	return hashes, err
+}
+
+// GetStakingTransaction looks up a previously stored staking
+// transaction by its hash.
+func (s *IndexerStore) GetStakingTransaction(hash *chainhash.Hash) (*StoredStakingTransaction, error) {
+	var stored *StoredStakingTransaction
+
+	err := kvdb.View(s.db, func(dbTx kvdb.RTx) error {
+		bucket := dbTx.ReadBucket(stakingTxBucketName)
+		if bucket == nil {
+			return fmt.Errorf("staking tx bucket not found")
+		}
+
+		raw := bucket.Get(hash[:])
+		if raw == nil {
+			return ErrTransactionNotFound
+		}
+
+		var err error
+		stored, err = unmarshalStoredStakingTx(raw)
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+func marshalStoredStakingTx(s *StoredStakingTransaction) ([]byte, error) {
+	var buf bytes.Buffer
+
+	txBytes, err := serializeTx(s.Tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeBytes(&buf, txBytes); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(&buf, binary.BigEndian, s.StakingOutputIdx); err != nil {
+		return nil, err
+	}
+	if err := writeBytes(&buf, s.StakerPk.SerializeCompressed()); err != nil {
+		return nil, err
+	}
+	if err := writeBytes(&buf, s.FinalityProviderPk.SerializeCompressed()); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(&buf, binary.BigEndian, s.StakingTime); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(&buf, binary.BigEndian, s.ParamsVersion); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(&buf, binary.BigEndian, s.InclusionHeight); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(&buf, binary.BigEndian, s.InclusionTime.Unix()); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func unmarshalStoredStakingTx(raw []byte) (*StoredStakingTransaction, error) {
+	r := bytes.NewReader(raw)
+
+	txBytes, err := readBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := deserializeTx(txBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var outIdx uint32
+	if err := binary.Read(r, binary.BigEndian, &outIdx); err != nil {
+		return nil, err
+	}
+
+	stakerPkBytes, err := readBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	stakerPk, err := btcec.ParsePubKey(stakerPkBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	fpPkBytes, err := readBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	fpPk, err := btcec.ParsePubKey(fpPkBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var stakingTime, paramsVersion uint32
+	var inclusionHeight uint64
+	var inclusionUnix int64
+	if err := binary.Read(r, binary.BigEndian, &stakingTime); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, binary.BigEndian, &paramsVersion); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, binary.BigEndian, &inclusionHeight); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, binary.BigEndian, &inclusionUnix); err != nil {
+		return nil, err
+	}
+
+	return &StoredStakingTransaction{
+		Tx:                 tx,
+		StakingOutputIdx:   outIdx,
+		StakerPk:           stakerPk,
+		FinalityProviderPk: fpPk,
+		StakingTime:        stakingTime,
+		ParamsVersion:      paramsVersion,
+		InclusionHeight:    inclusionHeight,
+		InclusionTime:      time.Unix(inclusionUnix, 0),
+	}, nil
+}
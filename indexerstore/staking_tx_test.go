@@ -0,0 +1,103 @@
+package indexerstore_test
+
+import (
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/staking-indexer/indexerstore"
+	"github.com/babylonchain/staking-indexer/testutils/datagen"
+)
+
+func openTestStore(t *testing.T) *indexerstore.IndexerStore {
+	dbPath := filepath.Join(t.TempDir(), "indexer.db")
+	db, err := kvdb.Create(kvdb.BoltBackendName, dbPath, true, 10*time.Second)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	store, err := indexerstore.NewIndexerStore(db)
+	require.NoError(t, err)
+
+	return store
+}
+
+// TestStakingTxSecondaryIndexes covers the staker-pk, finality-provider-pk
+// and height secondary indexes populated by AddStakingTransaction, which
+// back ListStakesByStakerPk/ListStakesByFinalityProviderPk/
+// ListActiveStakesAtHeight in the apiserver.
+func TestStakingTxSecondaryIndexes(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	store := openTestStore(t)
+	sysParams := datagen.GenerateGlobalParams(r, t)
+
+	data1 := datagen.GenerateTestStakingData(t, r)
+	parsed1, tx1 := datagen.GenerateStakingTxFromTestData(t, r, sysParams, data1)
+	require.NoError(t, store.AddStakingTransaction(tx1.MsgTx(), parsed1, sysParams.Version, 100, time.Now()))
+
+	data2 := datagen.GenerateTestStakingData(t, r)
+	parsed2, tx2 := datagen.GenerateStakingTxFromTestData(t, r, sysParams, data2)
+	require.NoError(t, store.AddStakingTransaction(tx2.MsgTx(), parsed2, sysParams.Version, 105, time.Now()))
+
+	// tx3 shares a staker with tx1 and a finality provider with tx2, at
+	// yet another height, to exercise every index independently.
+	data3 := &datagen.TestStakingData{
+		StakerKey:           data1.StakerKey,
+		FinalityProviderKey: data2.FinalityProviderKey,
+		StakingTime:         data1.StakingTime,
+		StakingAmount:       data1.StakingAmount,
+	}
+	parsed3, tx3 := datagen.GenerateStakingTxFromTestData(t, r, sysParams, data3)
+	require.NoError(t, store.AddStakingTransaction(tx3.MsgTx(), parsed3, sysParams.Version, 110, time.Now()))
+
+	hash1, hash2, hash3 := tx1.MsgTx().TxHash(), tx2.MsgTx().TxHash(), tx3.MsgTx().TxHash()
+
+	byStaker, err := store.GetStakingTxHashesByStakerPk(data1.StakerKey)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []chainhash.Hash{hash1, hash3}, byStaker)
+
+	byFp, err := store.GetStakingTxHashesByFinalityProviderPk(data2.FinalityProviderKey)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []chainhash.Hash{hash2, hash3}, byFp)
+
+	byHeight, err := store.GetStakingTxHashesByHeight(105)
+	require.NoError(t, err)
+	require.Equal(t, []chainhash.Hash{hash2}, byHeight)
+
+	byRange, err := store.GetStakingTxHashesByHeightRange(100, 110)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []chainhash.Hash{hash1, hash2, hash3}, byRange)
+
+	byEmptyRange, err := store.GetStakingTxHashesByHeightRange(0, 99)
+	require.NoError(t, err)
+	require.Empty(t, byEmptyRange)
+}
+
+// TestGetStakingTxHashesByHeightRangeSparseHeights covers a range that
+// spans a realistic BTC-sized gap between two sparsely populated
+// heights, asserting the transactions at both ends of the range are
+// returned.
+func TestGetStakingTxHashesByHeightRangeSparseHeights(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	store := openTestStore(t)
+	sysParams := datagen.GenerateGlobalParams(r, t)
+
+	const lowHeight = 1
+	const highHeight = 800_000
+
+	dataLow := datagen.GenerateTestStakingData(t, r)
+	parsedLow, txLow := datagen.GenerateStakingTxFromTestData(t, r, sysParams, dataLow)
+	require.NoError(t, store.AddStakingTransaction(txLow.MsgTx(), parsedLow, sysParams.Version, lowHeight, time.Now()))
+
+	dataHigh := datagen.GenerateTestStakingData(t, r)
+	parsedHigh, txHigh := datagen.GenerateStakingTxFromTestData(t, r, sysParams, dataHigh)
+	require.NoError(t, store.AddStakingTransaction(txHigh.MsgTx(), parsedHigh, sysParams.Version, highHeight, time.Now()))
+
+	hashes, err := store.GetStakingTxHashesByHeightRange(lowHeight, highHeight)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []chainhash.Hash{txLow.MsgTx().TxHash(), txHigh.MsgTx().TxHash()}, hashes)
+}
@@ -0,0 +1,98 @@
+package indexerstore
+
+import (
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// stakingTxBucketName stores txHash -> serialized StoredStakingTransaction.
+	stakingTxBucketName = []byte("stakingTxs")
+	// unbondingTxBucketName stores txHash -> serialized StoredUnbondingTransaction.
+	unbondingTxBucketName = []byte("unbondingTxs")
+
+	// stakerIndexBucketName stores stakerPk -> concatenated staking txHashes.
+	stakerIndexBucketName = []byte("stakerIndex")
+	// fpIndexBucketName stores finalityProviderPk -> concatenated staking txHashes.
+	fpIndexBucketName = []byte("fpIndex")
+	// heightIndexBucketName stores a sub-bucket per staking height,
+	// height -> {txHash -> struct{}}, so a height range can be scanned
+	// in order.
+	heightIndexBucketName = []byte("heightIndex")
+	// unbondingByStakingIndexBucketName stores stakingTxHash -> unbondingTxHash.
+	unbondingByStakingIndexBucketName = []byte("unbondingByStakingIndex")
+
+	// pendingStakingTxBucketName stores txHash -> serialized
+	// StoredPendingTransaction for staking transactions seen in the
+	// mempool but not yet confirmed.
+	pendingStakingTxBucketName = []byte("pendingStakingTxs")
+	// pendingUnbondingTxBucketName stores txHash -> serialized
+	// StoredPendingTransaction for unbonding transactions seen in the
+	// mempool but not yet confirmed.
+	pendingUnbondingTxBucketName = []byte("pendingUnbondingTxs")
+
+	// changelogBucketName holds one sub-bucket per BTC height,
+	// height -> {txHash -> OpKind byte}, recording every
+	// store mutation applied while processing that height so it can
+	// be inverted by Rollback.
+	changelogBucketName = []byte("changelog")
+
+	// metaBucketName stores singleton values about the store itself.
+	metaBucketName = []byte("meta")
+	// lastProcessedHeightKey is the key under metaBucketName holding
+	// the last BTC height the indexer has finished processing.
+	lastProcessedHeightKey = []byte("lastProcessedHeight")
+)
+
+// IndexerStore persists the staking/unbonding transactions observed by
+// the indexer, plus any secondary indexes built on top of them.
+type IndexerStore struct {
+	db kvdb.Backend
+}
+
+// NewIndexerStore opens the store on top of the given db, creating the
+// top-level buckets if they don't already exist.
+func NewIndexerStore(db kvdb.Backend) (*IndexerStore, error) {
+	store := &IndexerStore{db: db}
+
+	if err := store.initBuckets(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func (s *IndexerStore) initBuckets() error {
+	return kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		if _, err := tx.CreateTopLevelBucket(stakingTxBucketName); err != nil {
+			return err
+		}
+		if _, err := tx.CreateTopLevelBucket(unbondingTxBucketName); err != nil {
+			return err
+		}
+		if _, err := tx.CreateTopLevelBucket(stakerIndexBucketName); err != nil {
+			return err
+		}
+		if _, err := tx.CreateTopLevelBucket(fpIndexBucketName); err != nil {
+			return err
+		}
+		if _, err := tx.CreateTopLevelBucket(heightIndexBucketName); err != nil {
+			return err
+		}
+		if _, err := tx.CreateTopLevelBucket(unbondingByStakingIndexBucketName); err != nil {
+			return err
+		}
+		if _, err := tx.CreateTopLevelBucket(pendingStakingTxBucketName); err != nil {
+			return err
+		}
+		if _, err := tx.CreateTopLevelBucket(pendingUnbondingTxBucketName); err != nil {
+			return err
+		}
+		if _, err := tx.CreateTopLevelBucket(changelogBucketName); err != nil {
+			return err
+		}
+		if _, err := tx.CreateTopLevelBucket(metaBucketName); err != nil {
+			return err
+		}
+		return nil
+	}, func() {})
+}
@@ -0,0 +1,187 @@
+package indexerstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// StoredUnbondingTransaction is the representation of an unbonding
+// transaction as persisted by the indexer.
+type StoredUnbondingTransaction struct {
+	Tx              *wire.MsgTx
+	StakingTxHash   *chainhash.Hash
+	InclusionHeight uint64
+	InclusionTime   time.Time
+}
+
+// AddUnbondingTransaction persists a newly observed unbonding
+// transaction, linked back to the staking transaction it unbonds.
+func (s *IndexerStore) AddUnbondingTransaction(
+	tx *wire.MsgTx,
+	stakingTxHash *chainhash.Hash,
+	inclusionHeight uint64,
+	inclusionTime time.Time,
+) error {
+	txHash := tx.TxHash()
+
+	stored := &StoredUnbondingTransaction{
+		Tx:              tx,
+		StakingTxHash:   stakingTxHash,
+		InclusionHeight: inclusionHeight,
+		InclusionTime:   inclusionTime,
+	}
+
+	marshalled, err := marshalStoredUnbondingTx(stored)
+	if err != nil {
+		return err
+	}
+
+	return kvdb.Update(s.db, func(dbTx kvdb.RwTx) error {
+		bucket := dbTx.ReadWriteBucket(unbondingTxBucketName)
+		if bucket == nil {
+			return fmt.Errorf("unbonding tx bucket not found")
+		}
+		if err := bucket.Put(txHash[:], marshalled); err != nil {
+			return err
+		}
+
+		byStaking := dbTx.ReadWriteBucket(unbondingByStakingIndexBucketName)
+		if byStaking == nil {
+			return fmt.Errorf("unbonding-by-staking index bucket not found")
+		}
+		if err := byStaking.Put(stakingTxHash[:], txHash[:]); err != nil {
+			return err
+		}
+
+		changelog := dbTx.ReadWriteBucket(changelogBucketName)
+		if changelog == nil {
+			return fmt.Errorf("changelog bucket not found")
+		}
+		return appendChangelog(changelog, inclusionHeight, txHash, OpAddUnbond)
+	}, func() {})
+}
+
+// GetUnbondingTxHashByStakingTxHash returns the hash of the unbonding
+// transaction spending the given staking transaction, if any has been
+// observed.
+func (s *IndexerStore) GetUnbondingTxHashByStakingTxHash(stakingTxHash *chainhash.Hash) (*chainhash.Hash, error) {
+	var unbondingTxHash *chainhash.Hash
+
+	err := kvdb.View(s.db, func(dbTx kvdb.RTx) error {
+		bucket := dbTx.ReadBucket(unbondingByStakingIndexBucketName)
+		if bucket == nil {
+			return fmt.Errorf("unbonding-by-staking index bucket not found")
+		}
+
+		raw := bucket.Get(stakingTxHash[:])
+		if raw == nil {
+			return ErrTransactionNotFound
+		}
+
+		hash, err := chainhash.NewHash(raw)
+		if err != nil {
+			return err
+		}
+		unbondingTxHash = hash
+		return nil
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return unbondingTxHash, nil
+}
+
+// GetUnbondingTransaction looks up a previously stored unbonding
+// transaction by its hash.
+func (s *IndexerStore) GetUnbondingTransaction(hash *chainhash.Hash) (*StoredUnbondingTransaction, error) {
+	var stored *StoredUnbondingTransaction
+
+	err := kvdb.View(s.db, func(dbTx kvdb.RTx) error {
+		bucket := dbTx.ReadBucket(unbondingTxBucketName)
+		if bucket == nil {
+			return fmt.Errorf("unbonding tx bucket not found")
+		}
+
+		raw := bucket.Get(hash[:])
+		if raw == nil {
+			return ErrTransactionNotFound
+		}
+
+		var err error
+		stored, err = unmarshalStoredUnbondingTx(raw)
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+func marshalStoredUnbondingTx(s *StoredUnbondingTransaction) ([]byte, error) {
+	var buf bytes.Buffer
+
+	txBytes, err := serializeTx(s.Tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeBytes(&buf, txBytes); err != nil {
+		return nil, err
+	}
+	if err := writeBytes(&buf, s.StakingTxHash[:]); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(&buf, binary.BigEndian, s.InclusionHeight); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(&buf, binary.BigEndian, s.InclusionTime.Unix()); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func unmarshalStoredUnbondingTx(raw []byte) (*StoredUnbondingTransaction, error) {
+	r := bytes.NewReader(raw)
+
+	txBytes, err := readBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := deserializeTx(txBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	stakingTxHashBytes, err := readBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	stakingTxHash, err := chainhash.NewHash(stakingTxHashBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var inclusionHeight uint64
+	var inclusionUnix int64
+	if err := binary.Read(r, binary.BigEndian, &inclusionHeight); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, binary.BigEndian, &inclusionUnix); err != nil {
+		return nil, err
+	}
+
+	return &StoredUnbondingTransaction{
+		Tx:              tx,
+		StakingTxHash:   stakingTxHash,
+		InclusionHeight: inclusionHeight,
+		InclusionTime:   time.Unix(inclusionUnix, 0),
+	}, nil
+}
@@ -0,0 +1,106 @@
+package params
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+
+	"github.com/babylonchain/staking-indexer/types"
+)
+
+const defaultParamsFilename = "params.json"
+
+// paramsJSON mirrors the on-disk representation of types.Params.
+type paramsJSON struct {
+	Version          uint32   `json:"version"`
+	Tag              string   `json:"tag"`
+	CovenantPks      []string `json:"covenant_pks"`
+	CovenantQuorum   uint32   `json:"covenant_quorum"`
+	UnbondingTime    uint16   `json:"unbonding_time"`
+	UnbondingFee     int64    `json:"unbonding_fee"`
+	MinStakingAmount int64    `json:"min_staking_amount"`
+	MaxStakingAmount int64    `json:"max_staking_amount"`
+	ActivationHeight int32    `json:"activation_height"`
+}
+
+// Retriever is implemented by anything that can hand back the staking
+// params the indexer should use.
+type Retriever interface {
+	GetParams() *types.Params
+}
+
+// LocalParamsRetriever reads a single, static set of params from a
+// local JSON file.
+type LocalParamsRetriever struct {
+	params *types.Params
+}
+
+var _ Retriever = (*LocalParamsRetriever)(nil)
+
+// NewLocalParamsRetriever loads the params file found at the given
+// path, or "<path>/params.json" if path is a directory.
+func NewLocalParamsRetriever(path string) (*LocalParamsRetriever, error) {
+	filePath := path
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		filePath = filepath.Join(path, defaultParamsFilename)
+	}
+
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read params file %s: %w", filePath, err)
+	}
+
+	var raw paramsJSON
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse params file %s: %w", filePath, err)
+	}
+
+	p, err := raw.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid params file %s: %w", filePath, err)
+	}
+
+	return &LocalParamsRetriever{params: p}, nil
+}
+
+// GetParams returns the loaded params.
+func (r *LocalParamsRetriever) GetParams() *types.Params {
+	return r.params
+}
+
+func (raw *paramsJSON) parse() (*types.Params, error) {
+	tag, err := hex.DecodeString(raw.Tag)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tag: %w", err)
+	}
+
+	covenantPks := make([]*btcec.PublicKey, 0, len(raw.CovenantPks))
+	for _, pkHex := range raw.CovenantPks {
+		pkBytes, err := hex.DecodeString(pkHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid covenant pk: %w", err)
+		}
+		pk, err := btcec.ParsePubKey(pkBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid covenant pk: %w", err)
+		}
+		covenantPks = append(covenantPks, pk)
+	}
+
+	return &types.Params{
+		Version:          raw.Version,
+		Tag:              tag,
+		CovenantPks:      covenantPks,
+		CovenantQuorum:   raw.CovenantQuorum,
+		UnbondingTime:    raw.UnbondingTime,
+		UnbondingFee:     btcutil.Amount(raw.UnbondingFee),
+		MinStakingAmount: btcutil.Amount(raw.MinStakingAmount),
+		MaxStakingAmount: btcutil.Amount(raw.MaxStakingAmount),
+		ActivationHeight: raw.ActivationHeight,
+	}, nil
+}
@@ -0,0 +1,53 @@
+// Package service composes a StakingIndexer with the optional HTTP
+// query API layered on top of it, so a caller only has one Start/Stop
+// lifecycle to manage instead of driving each independently.
+package service
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/staking-indexer/apiserver"
+	"github.com/babylonchain/staking-indexer/config"
+	"github.com/babylonchain/staking-indexer/indexer"
+)
+
+// Service starts and stops a StakingIndexer and the apiserver.Server
+// built on top of it together.
+type Service struct {
+	si  *indexer.StakingIndexer
+	api *apiserver.Server
+}
+
+// New wires si to an apiserver.Server built from cfg.ApiServerConfig.
+// The API server remains a no-op on Start/Stop if it's disabled in
+// cfg.
+func New(cfg *config.Config, logger *zap.Logger, si *indexer.StakingIndexer) *Service {
+	return &Service{
+		si:  si,
+		api: apiserver.New(cfg.ApiServerConfig, logger, si),
+	}
+}
+
+// Start starts the indexer from startHeight, then the API server.
+func (s *Service) Start(startHeight int32) error {
+	if err := s.si.Start(startHeight); err != nil {
+		return fmt.Errorf("failed to start indexer: %w", err)
+	}
+
+	if err := s.api.Start(); err != nil {
+		return fmt.Errorf("failed to start api server: %w", err)
+	}
+
+	return nil
+}
+
+// Stop stops the API server, then the indexer.
+func (s *Service) Stop() error {
+	if err := s.api.Stop(); err != nil {
+		return fmt.Errorf("failed to stop api server: %w", err)
+	}
+
+	return s.si.Stop()
+}
@@ -0,0 +1,61 @@
+package service_test
+
+import (
+	"math/rand"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/staking-indexer/config"
+	"github.com/babylonchain/staking-indexer/indexer"
+	"github.com/babylonchain/staking-indexer/service"
+	"github.com/babylonchain/staking-indexer/testutils/datagen"
+	"github.com/babylonchain/staking-indexer/testutils/mocks"
+	"github.com/babylonchain/staking-indexer/types"
+)
+
+// TestServiceStartsApiServerAlongsideIndexer asserts that starting a
+// Service brings up both the indexer's processing loops and its HTTP
+// query API in one call, and that stopping it tears both down.
+func TestServiceStartsApiServerAlongsideIndexer(t *testing.T) {
+	homePath := filepath.Join(t.TempDir(), "indexer")
+	cfg := config.DefaultConfigWithHome(homePath)
+	cfg.ApiServerConfig.Enable = true
+	cfg.ApiServerConfig.Port = 19792
+
+	db, err := cfg.DatabaseConfig.GetDbBackend()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, db.Close()) }()
+
+	r := rand.New(rand.NewSource(1))
+	sysParams := datagen.GenerateGlobalParams(r, t)
+
+	ctrl := gomock.NewController(t)
+	mockScanner := mocks.NewMockBtcScanner(ctrl)
+	mockScanner.EXPECT().Start(gomock.Any()).Return(nil)
+	mockScanner.EXPECT().Stop().Return(nil)
+	mockScanner.EXPECT().ConfirmedBlocksChan().Return(make(chan *types.IndexedBlock)).AnyTimes()
+	mockScanner.EXPECT().MempoolTxsChan().Return(make(<-chan *btcutil.Tx)).AnyTimes()
+	mockScanner.EXPECT().ReorgChan().Return(make(<-chan int32)).AnyTimes()
+
+	mockConsumer := mocks.NewMockEventConsumer(ctrl)
+	mockConsumer.EXPECT().Start().Return(nil)
+	mockConsumer.EXPECT().Stop().Return(nil)
+
+	si, err := indexer.NewStakingIndexer(cfg, zap.NewNop(), mockConsumer, db, sysParams, mockScanner)
+	require.NoError(t, err)
+
+	svc := service.New(cfg, zap.NewNop(), si)
+	require.NoError(t, svc.Start(1))
+	defer func() { require.NoError(t, svc.Stop()) }()
+
+	resp, err := http.Get("http://127.0.0.1:19792/v1/status")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
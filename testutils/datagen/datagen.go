@@ -0,0 +1,172 @@
+package datagen
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/babylonchain/babylon/btcstaking"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/staking-indexer/types"
+)
+
+const stakingTag = "bbt4"
+
+// TestStakingData bundles the random staking parameters used to build
+// a staking transaction and its matching unbonding transaction in a
+// test.
+type TestStakingData struct {
+	StakerKey           *btcec.PublicKey
+	FinalityProviderKey *btcec.PublicKey
+	StakingTime         uint16
+	StakingAmount       btcutil.Amount
+
+	stakerPrivKey *btcec.PrivateKey
+}
+
+// GenerateTestStakingData returns a random, internally consistent set
+// of staking data to be used by GenerateStakingTxFromTestData and
+// GenerateUnbondingTxFromStaking.
+func GenerateTestStakingData(t *testing.T, r *rand.Rand) *TestStakingData {
+	stakerPriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	fpPriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return &TestStakingData{
+		StakerKey:           stakerPriv.PubKey(),
+		FinalityProviderKey: fpPriv.PubKey(),
+		StakingTime:         uint16(r.Int31n(60000) + 1),
+		StakingAmount:       btcutil.Amount(r.Int63n(1_000_000) + 10_000),
+		stakerPrivKey:       stakerPriv,
+	}
+}
+
+// GenerateGlobalParams returns a random set of staking params, with a
+// covenant committee of 3-of-5.
+func GenerateGlobalParams(r *rand.Rand, t *testing.T) *types.Params {
+	const numCovenant = 5
+	const quorum = 3
+
+	covenantPks := make([]*btcec.PublicKey, 0, numCovenant)
+	for i := 0; i < numCovenant; i++ {
+		priv, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+		covenantPks = append(covenantPks, priv.PubKey())
+	}
+
+	return &types.Params{
+		Version:          0,
+		Tag:              []byte(stakingTag),
+		CovenantPks:      covenantPks,
+		CovenantQuorum:   quorum,
+		UnbondingTime:    uint16(r.Int31n(1000) + 100),
+		UnbondingFee:     btcutil.Amount(1000),
+		MinStakingAmount: btcutil.Amount(1000),
+		MaxStakingAmount: btcutil.Amount(1_000_000_000),
+	}
+}
+
+// GenerateStakingTxFromTestData builds a V0 staking transaction
+// encoding data under params, together with the parsed view of it
+// that the indexer would produce after scanning it off the chain.
+func GenerateStakingTxFromTestData(
+	t *testing.T,
+	r *rand.Rand,
+	params *types.Params,
+	data *TestStakingData,
+) (*btcstaking.ParsedV0StakingTx, *btcutil.Tx) {
+	stakingOutput := &wire.TxOut{
+		Value:    int64(data.StakingAmount),
+		PkScript: randomPkScript(t, r),
+	}
+
+	opReturnData := &btcstaking.V0OpReturnData{
+		MagicBytes:                params.Tag,
+		Version:                   0,
+		StakerPublicKey:           &btcstaking.XonlyPubKey{PubKey: data.StakerKey},
+		FinalityProviderPublicKey: &btcstaking.XonlyPubKey{PubKey: data.FinalityProviderKey},
+		StakingTime:               data.StakingTime,
+	}
+
+	opReturnOutput := &wire.TxOut{
+		Value:    0,
+		PkScript: opReturnData.ToPkScript(),
+	}
+
+	msgTx := &wire.MsgTx{
+		Version: 2,
+		TxIn: []*wire.TxIn{
+			randomTxIn(r),
+		},
+		TxOut: []*wire.TxOut{stakingOutput, opReturnOutput},
+	}
+
+	parsed := &btcstaking.ParsedV0StakingTx{
+		StakingOutput:     stakingOutput,
+		StakingOutputIdx:  0,
+		OpReturnOutput:    opReturnOutput,
+		OpReturnOutputIdx: 1,
+		OpReturnData:      opReturnData,
+	}
+
+	return parsed, btcutil.NewTx(msgTx)
+}
+
+// GenerateUnbondingTxFromStaking builds an unbonding transaction
+// spending the staking output at stakingOutputIdx of the transaction
+// identified by stakingTxHash.
+func GenerateUnbondingTxFromStaking(
+	t *testing.T,
+	params *types.Params,
+	data *TestStakingData,
+	stakingTxHash *chainhash.Hash,
+	stakingOutputIdx uint32,
+) *btcutil.Tx {
+	unbondingValue := int64(data.StakingAmount) - int64(params.UnbondingFee)
+	require.Greater(t, unbondingValue, int64(0))
+
+	msgTx := &wire.MsgTx{
+		Version: 2,
+		TxIn: []*wire.TxIn{
+			wire.NewTxIn(wire.NewOutPoint(stakingTxHash, stakingOutputIdx), nil, nil),
+		},
+		TxOut: []*wire.TxOut{
+			{
+				Value:    unbondingValue,
+				PkScript: randomPkScript(t, nil),
+			},
+		},
+	}
+
+	return btcutil.NewTx(msgTx)
+}
+
+func randomTxIn(r *rand.Rand) *wire.TxIn {
+	var prevHash chainhash.Hash
+	if r != nil {
+		_, _ = r.Read(prevHash[:])
+	}
+	return wire.NewTxIn(wire.NewOutPoint(&prevHash, 0), nil, nil)
+}
+
+// randomPkScript returns a throwaway P2TR-sized script. Its exact
+// spendability doesn't matter to the indexer tests, which only assert
+// on the parsed staker/finality-provider/staking-time fields.
+func randomPkScript(t *testing.T, _ *rand.Rand) []byte {
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	builder := txscript.NewScriptBuilder()
+	builder.AddOp(txscript.OP_1)
+	builder.AddData(priv.PubKey().SerializeCompressed()[1:])
+	script, err := builder.Script()
+	require.NoError(t, err)
+
+	return script
+}
@@ -0,0 +1,216 @@
+// Package simbtc provides a deterministic, in-memory stand-in for a
+// real BTC node, for indexer tests that need to exercise confirmation
+// depth and reorg handling without standing up a regtest node.
+package simbtc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+
+	"github.com/babylonchain/staking-indexer/types"
+)
+
+// confirmedBlocksChanCap is generous enough that Mine/Reorg never
+// block on delivery within a single test.
+const confirmedBlocksChanCap = 1024
+
+type minedBlock struct {
+	height    int32
+	msgBlock  *wire.MsgBlock
+	delivered bool
+}
+
+// SimulatedBtcChain is an in-memory BTC chain that a test can mine
+// blocks onto and reorg, and that implements types.BtcScanner so a
+// StakingIndexer can be driven against it unchanged.
+type SimulatedBtcChain struct {
+	mu sync.Mutex
+
+	startHeight       int32
+	confirmationDepth int32
+
+	blocks []*minedBlock
+
+	confirmedBlocksChan chan *types.IndexedBlock
+	mempoolTxsChan      chan *btcutil.Tx
+	reorgChan           chan int32
+}
+
+var _ types.BtcScanner = (*SimulatedBtcChain)(nil)
+
+// NewSimulatedBtcChain creates a chain starting at startHeight, with
+// no blocks mined yet, requiring confirmationDepth confirmations
+// before a block is delivered to the indexer.
+func NewSimulatedBtcChain(startHeight int32, confirmationDepth int32) *SimulatedBtcChain {
+	return &SimulatedBtcChain{
+		startHeight:         startHeight,
+		confirmationDepth:   confirmationDepth,
+		confirmedBlocksChan: make(chan *types.IndexedBlock, confirmedBlocksChanCap),
+		mempoolTxsChan:      make(chan *btcutil.Tx, confirmedBlocksChanCap),
+		reorgChan:           make(chan int32, confirmedBlocksChanCap),
+	}
+}
+
+// Start implements types.BtcScanner. The simulated chain has no
+// background work to start; blocks are only produced by explicit
+// calls to Mine/Reorg.
+func (c *SimulatedBtcChain) Start(int32) error {
+	return nil
+}
+
+// Stop implements types.BtcScanner.
+func (c *SimulatedBtcChain) Stop() error {
+	return nil
+}
+
+// ConfirmedBlocksChan implements types.BtcScanner.
+func (c *SimulatedBtcChain) ConfirmedBlocksChan() chan *types.IndexedBlock {
+	return c.confirmedBlocksChan
+}
+
+// MempoolTxsChan implements types.BtcScanner.
+func (c *SimulatedBtcChain) MempoolTxsChan() <-chan *btcutil.Tx {
+	return c.mempoolTxsChan
+}
+
+// ReorgChan implements types.BtcScanner.
+func (c *SimulatedBtcChain) ReorgChan() <-chan int32 {
+	return c.reorgChan
+}
+
+// BroadcastToMempool delivers tx to the indexer's mempool feed,
+// without mining it into a block.
+func (c *SimulatedBtcChain) BroadcastToMempool(tx *btcutil.Tx) {
+	c.mempoolTxsChan <- tx
+}
+
+// TipHeight returns the height of the most recently mined block.
+func (c *SimulatedBtcChain) TipHeight() int32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.tipHeight()
+}
+
+func (c *SimulatedBtcChain) tipHeight() int32 {
+	return c.startHeight + int32(len(c.blocks)) - 1
+}
+
+// Mine appends a new block containing txs to the tip of the chain,
+// and delivers to the indexer every block that has just reached the
+// configured confirmation depth.
+func (c *SimulatedBtcChain) Mine(txs ...*btcutil.Tx) int32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.mine(txs)
+}
+
+func (c *SimulatedBtcChain) mine(txs []*btcutil.Tx) int32 {
+	height := c.tipHeight() + 1
+	if len(c.blocks) == 0 {
+		height = c.startHeight
+	}
+
+	rawTxs := make([]*wire.MsgTx, 0, len(txs))
+	for _, tx := range txs {
+		rawTxs = append(rawTxs, tx.MsgTx())
+	}
+
+	c.blocks = append(c.blocks, &minedBlock{
+		height: height,
+		msgBlock: &wire.MsgBlock{
+			Header:       wire.BlockHeader{Timestamp: time.Now()},
+			Transactions: rawTxs,
+		},
+	})
+
+	c.deliverConfirmed()
+
+	return height
+}
+
+// deliverConfirmed pushes every mined-but-undelivered block that has
+// reached confirmationDepth confirmations to confirmedBlocksChan, in
+// height order.
+func (c *SimulatedBtcChain) deliverConfirmed() {
+	confirmedTip := c.tipHeight() - c.confirmationDepth + 1
+
+	for _, b := range c.blocks {
+		if b.delivered || b.height > confirmedTip {
+			continue
+		}
+
+		txs := make([]*btcutil.Tx, 0, len(b.msgBlock.Transactions))
+		for _, tx := range b.msgBlock.Transactions {
+			txs = append(txs, btcutil.NewTx(tx))
+		}
+
+		c.confirmedBlocksChan <- &types.IndexedBlock{
+			Height: b.height,
+			Header: &b.msgBlock.Header,
+			Txs:    txs,
+		}
+		b.delivered = true
+	}
+}
+
+// Reorg replaces the last depth blocks of the chain with len(newTxs)
+// new blocks, each containing the corresponding entry of newTxs. It
+// only operates on blocks that have not yet been delivered to the
+// indexer (i.e. have not yet reached confirmationDepth confirmations),
+// matching what a real reorg below the indexer's confirmation depth
+// looks like.
+func (c *SimulatedBtcChain) Reorg(depth int, newTxs ...[]*btcutil.Tx) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if depth <= 0 || depth > len(c.blocks) {
+		return fmt.Errorf("invalid reorg depth %d for chain of %d blocks", depth, len(c.blocks))
+	}
+
+	reorgFrom := len(c.blocks) - depth
+	for _, b := range c.blocks[reorgFrom:] {
+		if b.delivered {
+			return fmt.Errorf("cannot reorg already-confirmed block at height %d", b.height)
+		}
+	}
+
+	c.blocks = c.blocks[:reorgFrom]
+
+	for _, txs := range newTxs {
+		c.mine(txs)
+	}
+
+	return nil
+}
+
+// DeepReorg discards every block above toHeight, including ones that
+// have already been delivered to the indexer as confirmed, and mines
+// len(newTxs) replacement blocks on top. Unlike Reorg, it notifies the
+// indexer via ReorgChan so it can roll back whatever it had already
+// persisted for the discarded heights before the replacement blocks
+// are delivered.
+func (c *SimulatedBtcChain) DeepReorg(toHeight int32, newTxs ...[]*btcutil.Tx) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if toHeight < c.startHeight-1 || toHeight > c.tipHeight() {
+		return fmt.Errorf("invalid deep reorg target height %d for chain tip %d", toHeight, c.tipHeight())
+	}
+
+	keep := toHeight - c.startHeight + 1
+	c.blocks = c.blocks[:keep]
+
+	c.reorgChan <- toHeight
+
+	for _, txs := range newTxs {
+		c.mine(txs)
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package testutils
+
+import "github.com/btcsuite/btcd/btcec/v2"
+
+// PubKeysEqual reports whether a and b represent the same public key.
+func PubKeysEqual(a, b *btcec.PublicKey) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.IsEqual(b)
+}
@@ -0,0 +1,54 @@
+package types
+
+import "github.com/btcsuite/btcd/chaincfg/chainhash"
+
+// StakingEvent is pushed to the EventConsumer whenever a new staking
+// transaction is confirmed and indexed.
+type StakingEvent struct {
+	StakingTxHash  chainhash.Hash
+	StakerPk       []byte
+	FinalityProvPk []byte
+	StakingAmount  int64
+	StakingTime    uint32
+}
+
+// UnbondingEvent is pushed to the EventConsumer whenever an unbonding
+// transaction spending a previously indexed staking transaction is
+// confirmed.
+type UnbondingEvent struct {
+	StakingTxHash   chainhash.Hash
+	UnbondingTxHash chainhash.Hash
+}
+
+// WithdrawEvent is pushed to the EventConsumer whenever the timelocked
+// path of a staking or unbonding output is spent.
+type WithdrawEvent struct {
+	StakingTxHash chainhash.Hash
+}
+
+// PendingStakingEvent is pushed to the EventConsumer whenever a
+// staking transaction is seen in the mempool, before it has been
+// confirmed.
+type PendingStakingEvent struct {
+	StakingTxHash  chainhash.Hash
+	StakerPk       []byte
+	FinalityProvPk []byte
+	StakingAmount  int64
+	StakingTime    uint32
+}
+
+// PendingUnbondingEvent is pushed to the EventConsumer whenever an
+// unbonding transaction spending a known staking transaction is seen
+// in the mempool, before it has been confirmed.
+type PendingUnbondingEvent struct {
+	StakingTxHash   chainhash.Hash
+	UnbondingTxHash chainhash.Hash
+}
+
+// RollbackEvent is pushed to the EventConsumer whenever the indexer
+// rolls back its store to height because of a reorg, invalidating
+// every previously-reported transaction in AffectedTxHashes.
+type RollbackEvent struct {
+	Height           int32
+	AffectedTxHashes []chainhash.Hash
+}
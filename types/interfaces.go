@@ -0,0 +1,56 @@
+package types
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// IndexedBlock is a BTC block together with the transactions
+// the scanner has decided are relevant for the indexer to look at.
+type IndexedBlock struct {
+	Height int32
+	Header *wire.BlockHeader
+	Txs    []*btcutil.Tx
+}
+
+// BtcScanner scans the BTC chain and delivers blocks/transactions
+// that the staking indexer should process.
+type BtcScanner interface {
+	// Start starts the scanner from the given height.
+	Start(startHeight int32) error
+	// Stop stops the scanner.
+	Stop() error
+	// ConfirmedBlocksChan returns the channel through which
+	// confirmed blocks are delivered to the indexer.
+	ConfirmedBlocksChan() chan *IndexedBlock
+	// MempoolTxsChan returns the channel through which transactions
+	// observed in the node's mempool, but not yet confirmed, are
+	// delivered to the indexer.
+	MempoolTxsChan() <-chan *btcutil.Tx
+	// ReorgChan returns the channel through which the scanner notifies
+	// the indexer that the chain has reorged back to the given
+	// height, i.e. every block above it should be considered invalid.
+	ReorgChan() <-chan int32
+}
+
+// EventConsumer is implemented by anything that wants to be notified
+// about staking-related events produced by the indexer.
+type EventConsumer interface {
+	PushStakingEvent(ev *StakingEvent) error
+	PushUnbondingEvent(ev *UnbondingEvent) error
+	PushWithdrawEvent(ev *WithdrawEvent) error
+	// PushPendingStakingEvent notifies the consumer about a staking
+	// transaction seen in the mempool, ahead of confirmation.
+	PushPendingStakingEvent(ev *PendingStakingEvent) error
+	// PushPendingUnbondingEvent notifies the consumer about an
+	// unbonding transaction seen in the mempool, ahead of
+	// confirmation.
+	PushPendingUnbondingEvent(ev *PendingUnbondingEvent) error
+	// PushRollbackEvent notifies the consumer that the indexer rolled
+	// back to height, invalidating every record for transactions in
+	// affectedTxHashes, so downstream consumers can compensate.
+	PushRollbackEvent(ev *RollbackEvent) error
+
+	Start() error
+	Stop() error
+}
@@ -0,0 +1,32 @@
+package types
+
+import (
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// Params holds the versioned set of staking parameters the indexer
+// uses to parse and validate staking/unbonding transactions.
+type Params struct {
+	// Version is the params version this set applies from.
+	Version uint32
+	// Tag is the magic bytes prefix used to identify a staking
+	// OP_RETURN output.
+	Tag []byte
+	// CovenantPks is the set of covenant committee public keys.
+	CovenantPks []*btcec.PublicKey
+	// CovenantQuorum is the number of covenant signatures required.
+	CovenantQuorum uint32
+	// UnbondingTime is the timelock, in BTC blocks, of the unbonding
+	// output.
+	UnbondingTime uint16
+	// UnbondingFee is the fixed fee an unbonding transaction must pay.
+	UnbondingFee btcutil.Amount
+	// MinStakingAmount and MaxStakingAmount bound the staking output
+	// value accepted by the indexer.
+	MinStakingAmount btcutil.Amount
+	MaxStakingAmount btcutil.Amount
+	// ActivationHeight is the BTC height from which this params
+	// version is active.
+	ActivationHeight int32
+}